@@ -0,0 +1,143 @@
+# backend/accessors.py
+"""
+Idiomatic-Go accessor handling (--idiomatic).
+
+Java beans carry getX/setX pairs that are redundant next to exported Go
+fields. This module classifies each class's accessors:
+
+- trivial (return x; / this.x = x;) → dropped, the field is exported
+- with logic (validation, recalculation, …) → the field stays unexported and
+  the accessors become X() / SetX(v) methods
+- computed getters with no backing field (getTotal) → X() methods
+
+The project-wide index lets every file rewrite its call sites to the same
+shapes, so cart.getQuantity() becomes cart.Quantity or cart.Quantity()
+depending on how CartItem was converted.
+"""
+import re
+
+from mybatis_mappers import exported, parse_fields
+from type_hierarchy import block_body
+
+CLASS_RE = re.compile(r"\bclass\s+([A-Z]\w*)")
+ACCESSOR_RE = re.compile(
+    r"^\s*public\s+(?:final\s+)?(?P<ret>[\w<>\[\],.?\s]+?)\s+(?P<name>(?:get|is|set)[A-Z]\w*)\s*"
+    r"\((?P<params>[^)]*)\)\s*(?:throws\s+[\w.,\s]+)?\{",
+    re.MULTILINE,
+)
+
+
+def _property(method_name):
+    prefix = "is" if method_name.startswith("is") else method_name[:3]
+    name = method_name[len(prefix):]
+    return prefix, name[:1].lower() + name[1:]
+
+
+def parse_accessors(source_code):
+    """
+    Return {"className", "fields", "properties"} for the first class in a Java
+    file, or None. properties maps a property name to
+    {"field": bool, "getter", "setter", "getterTrivial", "setterTrivial"}.
+    """
+    class_match = CLASS_RE.search(source_code)
+    if not class_match:
+        return None
+    fields = parse_fields(source_code)
+    properties = {}
+
+    for m in ACCESSOR_RE.finditer(source_code, class_match.end()):
+        prefix, prop = _property(m.group("name"))
+        params = [p for p in m.group("params").split(",") if p.strip()]
+        body = block_body(source_code, m.end() - 1).strip()
+        entry = properties.setdefault(prop, {
+            "field": prop in fields, "getter": None, "setter": None,
+            "getterTrivial": False, "setterTrivial": False,
+        })
+        if prefix == "set" and len(params) == 1:
+            param = params[0].split()[-1]
+            entry["setter"] = m.group("name")
+            entry["setterTrivial"] = bool(re.fullmatch(rf"(?:this\.)?{prop}\s*=\s*{param}\s*;", body))
+        elif prefix != "set" and not params:
+            entry["getter"] = m.group("name")
+            entry["getterTrivial"] = bool(re.fullmatch(rf"return\s+(?:this\.)?{prop}\s*;", body))
+
+    return {"className": class_match.group(1), "fields": fields, "properties": properties}
+
+
+def hidden_fields(accessors):
+    """Fields kept unexported because one of their accessors has logic."""
+    return {
+        prop for prop, p in accessors["properties"].items()
+        if p["field"] and ((p["getter"] and not p["getterTrivial"]) or (p["setter"] and not p["setterTrivial"]))
+    }
+
+
+def call_site_rules(accessors):
+    """Java call → Go call lines for one class, e.g. x.getQuantity() → x.Quantity."""
+    hidden = hidden_fields(accessors)
+    rules = []
+    for prop, p in sorted(accessors["properties"].items()):
+        go_name = exported(prop)
+        as_method = prop in hidden or not p["field"]
+        if p["getter"]:
+            rules.append(f"x.{p['getter']}() → x.{go_name}{'()' if as_method else ''}")
+        if p["setter"]:
+            rules.append(f"x.{p['setter']}(v) → " + (f"x.Set{go_name}(v)" if as_method else f"x.{go_name} = v"))
+    return rules
+
+
+def build_accessor_rules(source_code, index):
+    """
+    Return the prompt section for idiomatic accessors, or ''. index maps class
+    names across the project to parse_accessors results; classes referenced by
+    this file get call-site rewrites.
+    """
+    own = parse_accessors(source_code)
+    lines = []
+
+    if own and own["properties"]:
+        name = own["className"]
+        hidden = hidden_fields(own)
+        dropped, kept, computed = [], [], []
+        for prop, p in sorted(own["properties"].items()):
+            methods = "/".join(m for m in (p["getter"], p["setter"]) if m)
+            if not p["field"]:
+                computed.append(f"{p['getter'] or p['setter']} → {exported(prop)}()")
+            elif prop in hidden:
+                kept.append((prop, p))
+            else:
+                dropped.append(f"{methods} → {exported(prop)}")
+        lines.append(f"Idiomatic mode: do NOT emit Java-style Get/Set methods on {name}.")
+        if dropped:
+            lines.append(f"- drop trivial accessors and export the field: {'; '.join(dropped)}")
+        for prop, p in kept:
+            go_name = exported(prop)
+            methods = " and ".join(
+                m for m, present in ((f"{go_name}()", p["getter"]), (f"Set{go_name}(v)", p["setter"])) if present
+            )
+            lines.append(
+                f"- {prop} has accessor logic: keep the field unexported as {prop}, emit {methods} "
+                "methods carrying that logic"
+            )
+        if computed:
+            lines.append(f"- computed getters become methods without the Get prefix: {'; '.join(computed)}")
+
+    referenced = [
+        acc for class_name, acc in sorted((index or {}).items())
+        if acc["properties"] and re.search(rf"\b{class_name}\b", source_code)
+        and (not own or class_name != own["className"])
+    ]
+    if own and own["properties"]:
+        referenced.insert(0, own)
+    if referenced:
+        lines.append("Rewrite accessor calls to match how each class was converted:")
+        for acc in referenced:
+            lines.append(f"- {acc['className']}: " + "; ".join(call_site_rules(acc)))
+
+    return "\n".join(lines)
+
+
+def check_idiomatic_config(config):
+    """Idiomatic mode exports fields, which contradicts naming.fields: camelCase."""
+    if config and config["naming"]["fields"] != "exported":
+        raise ValueError("Idiomatic mode exports fields; it cannot be combined with naming.fields: camelCase")
@@ -0,0 +1,75 @@
+# backend/conversion_cache.py
+"""
+Content-hash cache for incremental project conversion.
+
+Each Java file is keyed by a hash of its source, the converter version and
+every option that shapes the prompt (package context, type mappings, config),
+so a re-run only reconverts files whose inputs actually changed.
+"""
+import hashlib
+import json
+import os
+
+CACHE_FILENAME = ".shadowcode-cache.json"
+
+
+def cache_key(source_code, converter_version, **inputs):
+    """Stable hash over the source, converter version and JSON-serialisable inputs."""
+    h = hashlib.sha256()
+    h.update(converter_version.encode())
+    h.update(b"\0")
+    h.update(source_code.encode())
+    h.update(b"\0")
+    h.update(json.dumps(inputs, sort_keys=True, default=str).encode())
+    return h.hexdigest()
+
+
+class ConversionCache:
+    def __init__(self, output_root):
+        self.output_root = output_root
+        self.path = os.path.join(output_root, CACHE_FILENAME)
+        self.entries = {}  # java path relative to source root -> {"key", "output"}
+        self.seen = set()
+        self.hits = 0
+        self.misses = 0
+
+        if os.path.exists(self.path):
+            try:
+                with open(self.path, "r") as f:
+                    self.entries = json.load(f).get("files", {})
+            except (OSError, ValueError):
+                print(f"⚠️  Ignoring unreadable cache {self.path}")
+                self.entries = {}
+
+    def mark_seen(self, rel_path):
+        """Keep rel_path's entry on save even if it is not looked up or stored this run."""
+        self.seen.add(rel_path)
+
+    def lookup(self, rel_path, key):
+        """Return the cached output path if rel_path is unchanged and its output still exists."""
+        self.mark_seen(rel_path)
+        entry = self.entries.get(rel_path)
+        if entry and entry["key"] == key:
+            out_path = os.path.join(self.output_root, entry["output"])
+            if os.path.exists(out_path):
+                self.hits += 1
+                return out_path
+        return None
+
+    def store(self, rel_path, key, out_path):
+        self.mark_seen(rel_path)
+        self.misses += 1
+        self.entries[rel_path] = {
+            "key": key,
+            "output": os.path.relpath(out_path, self.output_root),
+        }
+
+    def save(self):
+        """Persist the cache, dropping entries for Java files that no longer exist."""
+        stale = sorted(set(self.entries) - self.seen)
+        for rel_path in stale:
+            print(f"⚠️  {rel_path} was removed; its output {self.entries[rel_path]['output']} may be stale")
+            del self.entries[rel_path]
+
+        with open(self.path, "w") as f:
+            json.dump({"files": self.entries}, f, indent=2, sort_keys=True)
@@ -0,0 +1,115 @@
+# backend/conversion_config.py
+"""
+Per-project conversion config, so output can be tuned without editing the
+generated code. Read from shadowcode.yaml / shadowcode.yml / shadowcode.json:
+
+    typeMappings:             # fully-qualified Java type → Go type spec
+      java.util.Date: time.Time
+      java.math.BigDecimal: shopspring        # or bigfloat / import/path.Type
+      org.acme.Money: {goType: money.Amount, import: example.com/money}
+    naming:
+      fields: exported        # exported (Go style) | camelCase (keep Java names)
+    packageRenames:           # Java package → Go package directory
+      org.mybatis.jpetstore.domain: model
+"""
+import copy
+import json
+import os
+import re
+
+import yaml
+
+from go_imports import is_import_path
+from type_mappings import parse_type_spec, resolve_decimal_mapping
+
+CONFIG_FILENAMES = ("shadowcode.yaml", "shadowcode.yml", "shadowcode.json")
+FIELD_NAMING_STYLES = ("exported", "camelCase")
+
+DEFAULT_CONFIG = {
+    "typeMappings": {},
+    "naming": {"fields": "exported"},
+    "packageRenames": {},
+}
+
+
+def find_config(search_dir):
+    """Return the first config file in search_dir, or None."""
+    for name in CONFIG_FILENAMES:
+        path = os.path.join(search_dir, name)
+        if os.path.isfile(path):
+            return path
+    return None
+
+
+def load_conversion_config(path=None):
+    """
+    Load and validate a conversion config. Returns DEFAULT_CONFIG when path is
+    None; raises ValueError for unreadable or invalid files.
+    """
+    if not path:
+        return copy.deepcopy(DEFAULT_CONFIG)
+
+    try:
+        with open(path, "r") as f:
+            if path.endswith((".yaml", ".yml")):
+                raw = yaml.safe_load(f) or {}
+            else:
+                raw = json.load(f)
+    except (OSError, ValueError, yaml.YAMLError) as e:
+        raise ValueError(f"Failed to read conversion config {path}: {e}")
+
+    if not isinstance(raw, dict):
+        raise ValueError(f"Conversion config {path} must be a mapping at the top level")
+
+    config = copy.deepcopy(DEFAULT_CONFIG)
+
+    type_mappings = _section(raw, "typeMappings", path)
+    for java_type, spec in type_mappings.items():
+        if java_type == "java.math.BigDecimal":
+            resolve_decimal_mapping(spec)
+        else:
+            parse_type_spec(spec)
+    config["typeMappings"] = dict(type_mappings)
+
+    fields = _section(raw, "naming", path).get("fields", "exported")
+    if fields not in FIELD_NAMING_STYLES:
+        raise ValueError(f"naming.fields must be one of {', '.join(FIELD_NAMING_STYLES)}, got {fields!r}")
+    config["naming"]["fields"] = fields
+
+    for java_pkg, go_dir in _section(raw, "packageRenames", path).items():
+        config["packageRenames"][java_pkg] = _package_dir(java_pkg, go_dir)
+
+    return config
+
+
+def _section(raw, key, path):
+    """The mapping under key (empty when absent); anything else is a config error."""
+    value = raw.get(key) or {}
+    if not isinstance(value, dict):
+        raise ValueError(f"{key} in conversion config {path} must be a mapping, got {type(value).__name__}")
+    return value
+
+
+def _package_dir(java_pkg, go_dir):
+    """Validate a packageRenames target, which is joined onto the output directory."""
+    if not isinstance(go_dir, str):
+        raise ValueError(f"packageRenames.{java_pkg} must be a directory string, got {go_dir!r}")
+    if os.path.isabs(go_dir) or ".." in re.split(r"[/\\]", go_dir):
+        raise ValueError(f"packageRenames.{java_pkg} must be a relative path inside the module, got {go_dir!r}")
+    go_dir = go_dir.strip("/")
+    # The directory becomes part of every import path into the package.
+    if go_dir and not is_import_path(go_dir):
+        raise ValueError(
+            f"packageRenames.{java_pkg} must use only letters, digits and -._~+ in each path element, got {go_dir!r}"
+        )
+    return go_dir
+
+
+def build_naming_rules(config):
+    """Return the prompt section for naming overrides, or ''."""
+    if not config or config["naming"]["fields"] == "exported":
+        return ""
+    return (
+        "Keep Java field names as-is (camelCase, unexported) instead of exporting them; "
+        "access them from other packages through the getter/setter methods"
+    )
@@ -0,0 +1,69 @@
+# backend/conversion_pipeline.py
+"""
+Bounded, ordered worker pool for the project conversion pipeline.
+
+ProjectConverter runs its parse and transform stages through ordered_map:
+work fans out across a thread pool (Gemini calls are I/O bound), at most
+`window` results are held in memory at once, and results come back in input
+order so emitted files, cache entries and the report are deterministic.
+A failure in one item is returned alongside it instead of aborting the run.
+"""
+import os
+from collections import deque
+from concurrent.futures import ThreadPoolExecutor
+
+# Gemini rate limits, not CPU, bound useful parallelism.
+DEFAULT_WORKERS = 4
+
+
+def default_workers():
+    value = os.getenv("SHADOWCODE_WORKERS")
+    if value:
+        try:
+            return max(1, int(value))
+        except ValueError:
+            print(f"⚠️  Ignoring invalid SHADOWCODE_WORKERS={value!r}")
+    return DEFAULT_WORKERS
+
+
+def _capture(fn, item):
+    try:
+        return fn(item), None
+    except Exception as e:
+        return None, e
+
+
+def ordered_map(fn, items, workers=None, window=None):
+    """
+    Yield (item, result, error) for fn(item) over items, in input order.
+
+    At most `window` items (default 2 × workers) are submitted ahead of the
+    one being yielded, which bounds the results held in memory. error is the
+    exception fn raised for that item, or None.
+    """
+    workers = max(1, workers or default_workers())
+    window = max(1, window or workers * 2)
+
+    if workers == 1:
+        for item in items:
+            result, error = _capture(fn, item)
+            yield item, result, error
+        return
+
+    pending = deque()
+    remaining = iter(items)
+    with ThreadPoolExecutor(max_workers=workers) as pool:
+        for item in remaining:
+            pending.append((item, pool.submit(_capture, fn, item)))
+            if len(pending) >= window:
+                break
+        while pending:
+            item, future = pending.popleft()
+            result, error = future.result()
+            yield item, result, error
+            next_item = next(remaining, _DONE)
+            if next_item is not _DONE:
+                pending.append((next_item, pool.submit(_capture, fn, next_item)))
+
+
+_DONE = object()
@@ -0,0 +1,297 @@
+# backend/conversion_report.py
+"""
+Per-run conversion report listing Java constructs that were skipped or only
+approximated in Go, with file/line references, so migrators know exactly
+what to review by hand. Written as conversion-report.json and
+conversion-report.md next to the converted output, together with the
+go build / go test verification result when it ran.
+"""
+import json
+import os
+import re
+from collections import Counter
+from datetime import datetime, timezone
+
+from spring_controllers import parse_controller
+
+REPORT_JSON = "conversion-report.json"
+REPORT_MARKDOWN = "conversion-report.md"
+
+# (rule id, category, regex, message). "skipped" = no Go equivalent emitted,
+# "approximated" = translated with different semantics that need a check.
+CONSTRUCT_RULES = [
+    ("reflection", "approximated",
+     re.compile(r"\bClass\.forName\b|\.getDeclared(?:Method|Field|Constructor)s?\s*\(|\.getMethod\s*\(|"
+                r"\.invoke\s*\(|\bjava\.lang\.reflect\b"),
+     "Reflection has no direct Go equivalent; rewritten with the reflect package or static dispatch"),
+    ("checked-exception", "approximated",
+     re.compile(r"\)\s*throws\s+[\w.]+(?:\s*,\s*[\w.]+)*"),
+     "Checked exception became an error return; verify every caller handles the error"),
+    ("generic-wildcard", "approximated",
+     re.compile(r"<\s*\?\s*(?:extends|super)\b"),
+     "Wildcard generics (? extends / ? super) have no Go equivalent; approximated with type parameters or interfaces"),
+    ("generic-intersection-bound", "approximated",
+     re.compile(r"<\s*\w+\s+extends\s+[\w.<>]+\s*&"),
+     "Intersection type bounds (T extends A & B) approximated with a combined constraint interface"),
+    ("raw-type", "approximated",
+     re.compile(r"\b(?:List|Map|Set|Collection|Iterator|Class)\s+\w+\s*=\s*new\b(?!.*<)"),
+     "Raw collection type; element type was guessed"),
+    ("synchronized", "approximated",
+     re.compile(r"\bsynchronized\b"),
+     "synchronized translated to sync.Mutex; check lock scope and reentrancy (Go mutexes are not reentrant)"),
+    ("finally", "approximated",
+     re.compile(r"\bfinally\s*\{"),
+     "finally block translated to defer; check ordering relative to returns"),
+    ("anonymous-class", "approximated",
+     re.compile(r"\bnew\s+[A-Z]\w*(?:<[^>]*>)?\s*\([^)]*\)\s*\{"),
+     "Anonymous class translated to a closure or local struct"),
+    ("static-initializer", "approximated",
+     re.compile(r"^\s*static\s*\{", re.MULTILINE),
+     "Static initializer moved to init(); check package initialization order"),
+    ("varargs", "approximated",
+     re.compile(r"\w\s*\.\.\.\s*\w+\s*[,)]"),
+     "Varargs translated to a variadic slice parameter"),
+    ("serialization", "skipped",
+     re.compile(r"\bimplements\b[^{]*\bSerializable\b|\bserialVersionUID\b|\btransient\b"),
+     "Java serialization (Serializable, serialVersionUID, transient) dropped"),
+    ("framework-annotation", "skipped",
+     re.compile(r"@(?:Transactional|Component|Service|Repository|Entity|Table|Column|Id)\b"),
+     "Annotation has no Go equivalent; dropped or kept as a comment"),
+    ("spring-mvc-annotation", "skipped",
+     re.compile(r"@(?:Autowired|Controller|RestController|RequestMapping|GetMapping|PostMapping)\b"),
+     "Annotation has no Go equivalent; dropped or kept as a comment"),
+    ("native", "skipped",
+     re.compile(r"\bnative\s+[\w<>\[\]]+\s+\w+\s*\("),
+     "native method cannot be converted"),
+]
+
+METHOD_DECL_RE = re.compile(
+    r"^\s*(?:(?:public|protected|private|static|final|abstract|synchronized)\s+)*"
+    r"(?:<[^>]+>\s+)?[\w<>\[\],.?]+\s+(\w+)\s*\([^;{]*\)\s*(?:throws\s+[\w.,\s]+)?\{",
+    re.MULTILINE,
+)
+COMMENT_STRING_RE = re.compile(r'//[^\n]*|/\*.*?\*/|"(?:\\.|[^"\\\n])*"', re.DOTALL)
+
+
+def _blank_out(match):
+    """Replace comments/strings with spaces, keeping newlines so line numbers survive."""
+    return re.sub(r"[^\n]", " ", match.group(0))
+
+
+def analyze_constructs(source_code):
+    """
+    Return findings for constructs in a Java file that need manual review:
+    [{"rule", "category", "line", "snippet", "message"}], ordered by line.
+    """
+    code = COMMENT_STRING_RE.sub(_blank_out, source_code)
+    lines = source_code.splitlines()
+    findings = []
+
+    def add(rule, category, offset, message):
+        line = code.count("\n", 0, offset) + 1
+        snippet = lines[line - 1].strip() if line <= len(lines) else ""
+        findings.append({
+            "rule": rule,
+            "category": category,
+            "line": line,
+            "snippet": snippet[:160],
+            "message": message,
+        })
+
+    # Parsed controllers become handlers, routes.go and a constructor, so their
+    # mapping and injection annotations are converted rather than dropped.
+    controller = parse_controller(source_code) is not None
+    for rule, category, pattern, message in CONSTRUCT_RULES:
+        if controller and rule == "spring-mvc-annotation":
+            continue
+        for m in pattern.finditer(code):
+            add(rule, category, m.start(), message)
+
+    # Go has no method overloading: every overload after the first gets renamed.
+    seen = {}
+    for m in METHOD_DECL_RE.finditer(code):
+        name = m.group(1)
+        if name in seen:
+            add("overload", "approximated", m.start(1),
+                f"Overloaded method {name} (first declared on line {seen[name]}) must be renamed in Go")
+        else:
+            seen[name] = code.count("\n", 0, m.start(1)) + 1
+
+    findings.sort(key=lambda f: (f["line"], f["rule"]))
+    return findings
+
+
+class ConversionReport:
+    def __init__(self, source_root, output_root, converter_version):
+        self.source_root = source_root
+        self.output_root = output_root
+        self.converter_version = converter_version
+        self.started_at = datetime.now(timezone.utc).isoformat()
+        self.files = []
+        self.package_cycles = []
+        self.verification = None
+
+    def add_file(self, java_path, go_path, findings, status, error=None):
+        self.files.append({
+            "javaFile": os.path.relpath(java_path, self.source_root),
+            "goFile": os.path.relpath(go_path, self.output_root) if go_path else None,
+            "status": status,
+            "error": error,
+            "findings": findings,
+        })
+
+    def add_package_cycle(self, packages, edges):
+        """Record Go packages that import each other; edges name the Java files behind each import."""
+        self.package_cycles.append({"packages": packages, "edges": edges})
+
+    def add_verification(self, result):
+        """
+        Attach a go_verify.verify_module result, mapping every compiler error and
+        failing test back to the Java file its Go file was converted from.
+        """
+        by_go_file = {f["goFile"]: f for f in self.files if f["goFile"]}
+        for package in result["packages"]:
+            java_files = set()
+            for item in package["errors"] + package["failedTests"]:
+                go_file = os.path.relpath(item["goFile"], self.output_root) if item.get("goFile") else None
+                entry = by_go_file.get(go_file)
+                item["goFile"] = go_file
+                item["javaFile"] = entry["javaFile"] if entry else None
+                if entry:
+                    java_files.add(entry["javaFile"])
+                    if "name" in item:
+                        text = f"{item['name']} failed" + (f": {item['message']}" if item["message"] else "")
+                    else:
+                        text = item["message"]
+                    location = f"{go_file}:{item['line']}" if item["line"] else go_file
+                    entry.setdefault("verification", []).append(f"{location}: {text}")
+            package_dir = "" if package["dir"] == "." else package["dir"]
+            package["javaFiles"] = sorted(
+                {f["javaFile"] for f in self.files if f["goFile"] and os.path.dirname(f["goFile"]) == package_dir}
+                | java_files
+            )
+        self.verification = result
+
+    def summary(self):
+        statuses = Counter(f["status"] for f in self.files)
+        rules = Counter(finding["rule"] for f in self.files for finding in f["findings"])
+        summary = {
+            "files": len(self.files),
+            "statuses": dict(statuses),
+            "findings": sum(rules.values()),
+            "byRule": dict(rules.most_common()),
+        }
+        if self.verification:
+            packages = self.verification["packages"]
+            summary["verification"] = {
+                "status": self.verification["status"],
+                "packages": len(packages),
+                "failedPackages": sum(1 for p in packages if p["build"] != "passed" or p["tests"] == "failed"),
+            }
+        return summary
+
+    def to_dict(self):
+        return {
+            "sourceRoot": self.source_root,
+            "outputRoot": self.output_root,
+            "converterVersion": self.converter_version,
+            "startedAt": self.started_at,
+            "finishedAt": datetime.now(timezone.utc).isoformat(),
+            "summary": self.summary(),
+            "packageCycles": self.package_cycles,
+            "verification": self.verification,
+            "files": self.files,
+        }
+
+    def to_markdown(self):
+        summary = self.summary()
+        out = [
+            "# Conversion Report",
+            "",
+            f"- Source: `{self.source_root}`",
+            f"- Output: `{self.output_root}`",
+            f"- Converter version: {self.converter_version}",
+            f"- Files: {summary['files']} ({', '.join(f'{n} {s}' for s, n in sorted(summary['statuses'].items()))})",
+            f"- Constructs to review: {summary['findings']}",
+            "",
+        ]
+        if summary["byRule"]:
+            out += ["| Rule | Count |", "|---|---|"]
+            out += [f"| {rule} | {count} |" for rule, count in summary["byRule"].items()]
+            out.append("")
+
+        if self.package_cycles:
+            out += ["## Import cycles", ""]
+            for cycle in self.package_cycles:
+                out.append(f"- {', '.join(f'`{p}`' for p in cycle['packages'])}")
+                for edge in cycle["edges"]:
+                    sources = ", ".join(f"`{j}`" for j in edge["javaFiles"])
+                    out.append(f"  - `{edge['from']}` → `{edge['to']}` ({sources})")
+            out.append("")
+
+        out += self._verification_markdown()
+
+        for f in self.files:
+            if not f["findings"] and not f["error"] and not f.get("verification"):
+                continue
+            out.append(f"## {f['javaFile']}")
+            out.append("")
+            if f["goFile"]:
+                out.append(f"→ `{f['goFile']}` ({f['status']})")
+                out.append("")
+            if f["error"]:
+                out.append(f"**Error:** {f['error']}")
+                out.append("")
+            for failure in f.get("verification", []):
+                out.append(f"- **verification**: `{failure}`")
+            for finding in f["findings"]:
+                out.append(
+                    f"- L{finding['line']} **{finding['rule']}** ({finding['category']}): "
+                    f"{finding['message']} — `{finding['snippet']}`"
+                )
+            out.append("")
+
+        return "\n".join(out)
+
+    def _verification_markdown(self):
+        v = self.verification
+        if not v:
+            return []
+        out = ["## Verification", "", f"`go build` / `go test`: **{v['status']}**"]
+        if v.get("reason"):
+            out += ["", "```", v["reason"], "```"]
+        out.append("")
+        if v["packages"]:
+            out += ["| Package | Build | Tests | Java sources |", "|---|---|---|---|"]
+            for p in v["packages"]:
+                sources = ", ".join(f"`{j}`" for j in p["javaFiles"]) or "—"
+                build = f"blocked by `{p['blockedBy']}`" if p["blockedBy"] else p["build"]
+                out.append(f"| `{p['importPath']}` | {build} | {p['tests']} | {sources} |")
+            out.append("")
+        for p in v["packages"]:
+            for e in p["errors"]:
+                origin = f" (from `{e['javaFile']}`)" if e["javaFile"] else ""
+                out.append(f"- `{e['goFile']}:{e['line']}`{origin}: {e['message']}")
+            for t in p["failedTests"]:
+                origin = f" (from `{t['javaFile']}`)" if t["javaFile"] else ""
+                if t["goFile"]:
+                    where = f"`{t['goFile']}:{t['line']}`" if t["line"] else f"`{t['goFile']}`"
+                else:
+                    where = "an unknown file"
+                message = f": {t['message']}" if t["message"] else ""
+                out.append(f"- test **{t['name']}** failed in {where}{origin}{message}")
+        if any(p["errors"] or p["failedTests"] for p in v["packages"]):
+            out.append("")
+        return out
+
+    def write(self):
+        json_path = os.path.join(self.output_root, REPORT_JSON)
+        md_path = os.path.join(self.output_root, REPORT_MARKDOWN)
+        with open(json_path, "w") as f:
+            json.dump(self.to_dict(), f, indent=2)
+        with open(md_path, "w") as f:
+            f.write(self.to_markdown())
+
+        summary = self.summary()
+        print(f"📋 Report: {summary['findings']} constructs to review across {summary['files']} files → {md_path}")
+        return json_path, md_path
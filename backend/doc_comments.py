@@ -0,0 +1,255 @@
+# backend/doc_comments.py
+"""
+Carry Javadoc over to Go doc comments.
+
+Each /** ... */ block is attached to the type, method, constructor or field
+that follows it and rewritten in Go doc style: the first sentence starts
+with the Go identifier, {@link Foo#bar} becomes a [Foo.Bar] doc link,
+@param turns into a list, @deprecated into a "Deprecated:" paragraph.
+
+The converted comments go into the prompt so Gemini places them, and
+apply_doc_comments backfills any declaration that still lacks one.
+"""
+import re
+import textwrap
+
+from mybatis_mappers import exported
+
+JAVADOC_RE = re.compile(r"/\*\*(?!/)(.*?)\*/", re.DOTALL)
+ANNOTATION_RE = re.compile(r"\s*@\w+(?:\.\w+)*(?:\s*\((?:[^()]|\([^()]*\))*\))?")
+TYPE_DECL_RE = re.compile(r"\b(?:class|interface|enum|record)\s+([A-Z]\w*)")
+METHOD_NAME_RE = re.compile(r"(\w+)\s*\($")
+FIELD_NAME_RE = re.compile(r"(\w+)\s*$")
+INLINE_TAG_RE = re.compile(r"\{@(code|literal|link|linkplain|value)\s+([^}]*)\}")
+HTML_TAG_RE = re.compile(r"</?(?:b|i|em|strong|code|tt|pre|ul|ol|br)\s*/?>", re.IGNORECASE)
+DROPPED_TAGS = ("@author", "@version", "@since", "@serial")
+# Summary verbs that read naturally after the Go name ("Returns the total." → "Total returns the total.").
+# A bare "ends in s" check would also catch This, Its, Process or Class.
+LEAD_VERBS = {
+    "Adds", "Applies", "Builds", "Calculates", "Checks", "Clears", "Compares", "Computes", "Contains",
+    "Converts", "Counts", "Creates", "Decodes", "Defines", "Deletes", "Describes", "Determines", "Encodes",
+    "Executes", "Fetches", "Finds", "Formats", "Generates", "Gets", "Handles", "Holds", "Indicates",
+    "Initializes", "Lists", "Loads", "Looks", "Manages", "Maps", "Marks", "Parses", "Performs", "Provides",
+    "Reads", "Registers", "Removes", "Represents", "Resets", "Resolves", "Retrieves", "Returns", "Runs",
+    "Saves", "Sends", "Sets", "Starts", "Stops", "Stores", "Tests", "Updates", "Validates", "Wraps", "Writes",
+}
+
+
+def _link(target):
+    """{@link Foo#bar(int)} → [Foo.Bar]; {@link #bar} → [Bar]."""
+    target = target.strip().split()[0]
+    target = re.sub(r"\(.*", "", target)
+    owner, _, member = target.partition("#")
+    owner = owner.split(".")[-1]
+    if member:
+        return f"[{owner}.{exported(member)}]" if owner else f"[{exported(member)}]"
+    return f"[{owner}]"
+
+
+def _clean_javadoc(raw):
+    lines = []
+    for line in raw.splitlines():
+        line = re.sub(r"^\s*\*\s?", "", line).rstrip()
+        lines.append(line)
+    while lines and not lines[0].strip():
+        lines.pop(0)
+    while lines and not lines[-1].strip():
+        lines.pop()
+    return textwrap.dedent("\n".join(lines)).split("\n") if lines else []
+
+
+def _inline(text):
+    def replace(m):
+        tag, body = m.group(1), m.group(2)
+        if tag in ("link", "linkplain"):
+            return _link(body)
+        return body.strip()
+    text = INLINE_TAG_RE.sub(replace, text)
+    text = re.sub(r"<li>\s*", "  - ", text, flags=re.IGNORECASE)
+    text = re.sub(r"</li>", "", text, flags=re.IGNORECASE)
+    return HTML_TAG_RE.sub("", text).replace("&lt;", "<").replace("&gt;", ">").replace("&amp;", "&")
+
+
+def _lead(go_name, first_line):
+    """Start the first sentence with the Go identifier, as golint expects."""
+    stripped = first_line.strip()
+    if not stripped or stripped.startswith(go_name + " ") or stripped == go_name:
+        return first_line
+    first_word = stripped.split()[0]
+    if first_word in ("A", "An", "The"):
+        return f"{go_name} is {stripped[0].lower()}{stripped[1:]}"
+    if first_word in LEAD_VERBS:
+        return f"{go_name} {stripped[0].lower()}{stripped[1:]}"
+    return first_line
+
+
+def javadoc_to_go(raw, go_name, lead=True):
+    """Return the Go doc comment lines (with //) for a Javadoc body; lead prefixes the Go identifier."""
+    description, params, returns, throws, deprecated, see = [], [], [], [], [], []
+    current = description
+    for line in _clean_javadoc(raw):
+        stripped = line.strip()
+        if stripped.startswith("@"):
+            tag, _, rest = stripped.partition(" ")
+            rest = _inline(rest.strip())
+            if tag == "@param":
+                name, _, desc = rest.partition(" ")
+                params.append(f"  - {name}: {desc.strip()}" if desc.strip() else f"  - {name}")
+                current = params
+            elif tag == "@return":
+                returns.append(f"Returns {rest[:1].lower()}{rest[1:]}" if rest else "")
+                current = returns
+            elif tag in ("@throws", "@exception"):
+                exc, _, desc = rest.partition(" ")
+                throws.append(f"Returns an error ({exc.split('.')[-1]}) {desc.strip()}".rstrip())
+                current = throws
+            elif tag == "@deprecated":
+                deprecated.append(f"Deprecated: {rest}".rstrip())
+                current = deprecated
+            elif tag == "@see":
+                see.append(f"See {rest}.".replace("..", "."))
+                current = see
+            elif tag in DROPPED_TAGS:
+                current = []
+            else:
+                current = []
+            continue
+        if re.fullmatch(r"<p>|</p>", stripped, re.IGNORECASE):
+            current.append("")
+            continue
+        text = _inline(re.sub(r"^<p>\s*", "", line, flags=re.IGNORECASE))
+        if current is description:
+            current.append(text)
+        elif text.strip():
+            current[-1] = f"{current[-1]} {text.strip()}" if current else text.strip()
+
+    while description and not description[-1].strip():
+        description.pop()
+    if description and lead:
+        description[0] = _lead(go_name, description[0])
+
+    paragraphs = [p for p in (
+        description,
+        params,
+        [" ".join(l for l in returns if l)] if any(returns) else [],
+        throws,
+        see,
+        deprecated,
+    ) if p]
+    out = []
+    for i, para in enumerate(paragraphs):
+        if i:
+            out.append("//")
+        out += [f"// {l}".rstrip() if l.strip() else "//" for l in para]
+    return out
+
+
+def extract_javadoc(source_code):
+    """
+    Return [{"kind": "type" | "method" | "constructor" | "field", "name", "owner", "raw"}]
+    for every Javadoc block attached to a declaration.
+    """
+    types = [(m.start(), m.group(1)) for m in TYPE_DECL_RE.finditer(source_code)]
+    docs = []
+    for m in JAVADOC_RE.finditer(source_code):
+        pos = m.end()
+        while True:
+            a = ANNOTATION_RE.match(source_code, pos)
+            if not a or a.end() == pos:
+                break
+            pos = a.end()
+        end = len(source_code)
+        for stop in ("{", ";", "=", "/*"):
+            i = source_code.find(stop, pos)
+            if i != -1:
+                end = min(end, i)
+        decl = " ".join(source_code[pos:end].split())
+        if not decl:
+            continue
+
+        owner = next((name for start, name in reversed(types) if start < pos), None)
+        type_match = TYPE_DECL_RE.search(decl)
+        if type_match:
+            docs.append({"kind": "type", "name": type_match.group(1), "owner": owner, "raw": m.group(1)})
+            continue
+        paren = decl.find("(")
+        if paren != -1:
+            name_match = METHOD_NAME_RE.search(decl[: paren + 1])
+            if name_match:
+                name = name_match.group(1)
+                kind = "constructor" if name == owner else "method"
+                docs.append({"kind": kind, "name": name, "owner": owner, "raw": m.group(1)})
+            continue
+        name_match = FIELD_NAME_RE.search(decl)
+        if name_match:
+            docs.append({"kind": "field", "name": name_match.group(1), "owner": owner, "raw": m.group(1)})
+    return docs
+
+
+def go_names(doc):
+    """Candidate Go identifiers for a documented Java declaration, best first."""
+    name = doc["name"]
+    if doc["kind"] == "type":
+        return [name]
+    if doc["kind"] == "constructor":
+        return [f"New{name}"]
+    candidates = [exported(name), name]
+    accessor = re.match(r"(?:get|is)([A-Z]\w*)$", name)
+    if doc["kind"] == "method" and accessor:
+        candidates.append(accessor.group(1))
+    return candidates
+
+
+def build_comment_rules(source_code):
+    """Return the prompt section listing the Go doc comment for each declaration, or ''."""
+    docs = extract_javadoc(source_code)
+    if not docs and not re.search(r"//|/\*", source_code):
+        return ""
+    lines = [
+        "Keep the original documentation:",
+        "- place each doc comment below immediately above its Go declaration, verbatim",
+        "- keep inline // and /* */ comments next to the statements they describe, translated to // comments",
+    ]
+    for doc in docs:
+        owner = f"{doc['owner']}." if doc["owner"] and doc["kind"] != "type" else ""
+        go_name = go_names(doc)[0]
+        lines.append(f"{doc['kind']} {owner}{doc['name']} → {go_name}:")
+        lines += [f"  {l}" for l in javadoc_to_go(doc["raw"], go_name, doc["kind"] != "field")]
+    return "\n".join(lines)
+
+
+def _find_declaration(lines, doc):
+    """Index of the Go line declaring doc and the identifier matched, or (None, None)."""
+    for go_name in go_names(doc):
+        if doc["kind"] == "type":
+            patterns = [rf"^type\s+{go_name}\b"]
+        elif doc["kind"] == "field":
+            patterns = [rf"^\t{go_name}\s+[^\s(=]"]
+        else:
+            receiver = rf"\(\s*\w*\s*\*?{doc['owner']}(?:\[[^\]]*\])?\s*\)" if doc["owner"] else r"\([^)]*\)"
+            patterns = [
+                rf"^func\s+{receiver}\s*{go_name}\s*[\[(]",
+                rf"^func\s+(?:\([^)]*\)\s*)?{go_name}\s*[\[(]",
+                rf"^\t{go_name}\s*\(",  # interface method
+            ]
+        for pattern in patterns:
+            regex = re.compile(pattern)
+            for i, line in enumerate(lines):
+                if regex.search(line):
+                    return i, go_name
+    return None, None
+
+
+def apply_doc_comments(go_code, java_source):
+    """Insert converted Javadoc above Go declarations that have no doc comment yet."""
+    docs = extract_javadoc(java_source)
+    if not docs:
+        return go_code
+    lines = go_code.split("\n")
+    for doc in docs:
+        i, go_name = _find_declaration(lines, doc)
+        if i is None or (i > 0 and lines[i - 1].strip().startswith(("//", "*/"))):
+            continue
+        indent = re.match(r"\s*", lines[i]).group(0)
+        lines[i:i] = [indent + l for l in javadoc_to_go(doc["raw"], go_name, doc["kind"] != "field")]
+    return "\n".join(lines)
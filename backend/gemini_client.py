@@ -1,9 +1,17 @@
 import os
+import re
 import json
 import time
 import google.generativeai as genai
 from google.api_core import exceptions as google_exceptions
 
+from accessors import build_accessor_rules
+from conversion_config import build_naming_rules
+from doc_comments import build_comment_rules
+from spring_controllers import build_controller_rules
+from type_hierarchy import build_hierarchy_rules
+from type_mappings import build_type_mapping_rules
+
 
 def get_gemini_config():
     """Load Gemini credentials from environment variables."""
@@ -15,6 +23,10 @@ def get_gemini_config():
     return api_key
 
 
+# Bump whenever prompts or post-processing change, so cached conversions are redone.
+CONVERTER_VERSION = "1.3.0"
+
+
 # ─── System Prompt ────────────────────────────────────────────
 
 SYSTEM_PROMPT = """\
@@ -27,6 +39,8 @@ Your responsibilities:
    - Classes → structs with methods (receiver functions)
    - Interfaces → Go interfaces
    - Inheritance → composition (embedded structs)
+   - Abstract classes → a Go interface plus a <Name>Base struct that implementations embed
+   - Implementations → compile-time assertions: var _ Iface = (*Impl)(nil)
    - Exceptions → error returns (multiple return values)
    - Generics → Go generics (type parameters) where appropriate
    - Annotations → comments documenting the original annotation semantics
@@ -53,10 +67,65 @@ Constraints you MUST follow:
 """
 
 
-def build_conversion_prompt(source_code, node_analysis, file_path):
+# ─── JUnit Test Conversion ────────────────────────────────────
+
+JUNIT_RE = re.compile(r"^\s*import\s+(?:static\s+)?org\.junit\b|@(?:Parameterized)?Test\b", re.MULTILINE)
+
+JUNIT_TEST_RULES = """\
+This is a JUnit test class. Emit a Go _test.go file using testing and testify:
+- Keep the package of the code under test; import "testing", "github.com/stretchr/testify/assert" and, where needed, ".../testify/require"
+- @Test void fooBar() → func TestFooBar(t *testing.T); @DisplayName text becomes the t.Run subtest name
+- @ParameterizedTest with @ValueSource / @CsvSource / @MethodSource → a table-driven test:
+  tests := []struct{ name string; <inputs>; <want> }{...}; for _, tc := range tests { t.Run(tc.name, func(t *testing.T) { ... }) }
+- Several @Test methods that differ only in inputs and expected values → collapse into ONE table-driven test with a case per method, named after the original method
+- Keep given/when/then structure as // given, // when, // then comments inside each case
+- assertEquals(expected, actual) → assert.Equal(t, expected, actual); assertEquals(exp, act, delta) → assert.InDelta(t, exp, act, delta)
+- assertNotEquals → assert.NotEqual; assertSame → assert.Same; assertArrayEquals / assertIterableEquals → assert.Equal
+- assertTrue / assertFalse → assert.True / assert.False; assertNull / assertNotNull → assert.Nil / assert.NotNil
+- Use require.* instead of assert.* when later statements depend on the assertion (e.g. NotNil before dereferencing, NoError before using a result)
+- assertThrows(X.class, () -> call()) → _, err := call(); require.Error(t, err) (use assert.ErrorIs when the Go code exposes a sentinel error)
+- AssertJ assertThat(a).isEqualTo(b) → assert.Equal(t, b, a)
+- @BeforeEach → a setup helper called at the start of each test or subtest; @BeforeAll / @AfterAll → TestMain
+- @Disabled("reason") → t.Skip("reason")
+- Mockito mocks → hand-written fakes implementing the Go interface, or a TODO if the interface does not exist yet
+"""
+
+
+def is_junit_test(source_code):
+    """Return True if the Java source looks like a JUnit test class."""
+    return bool(JUNIT_RE.search(source_code))
+
+
+def go_output_filename(java_filename, source_code=""):
+    """
+    Map a Java file name to its Go file name: OrderService.java → orderservice.go,
+    and JUnit classes (OrderTest.java) → order_test.go so go test picks them up.
+    """
+    base = java_filename.replace(".java", "").lower()
+    if is_junit_test(source_code):
+        for suffix in ("tests", "test"):
+            if base.endswith(suffix) and len(base) > len(suffix):
+                base = base[: -len(suffix)]
+                break
+        return f"{base}_test.go"
+    return f"{base}.go"
+
+
+def build_conversion_prompt(source_code, node_analysis, file_path, package_context=None, decimal_type=None,
+                            known_kinds=None, config=None, accessors=None):
     """
     Build the user-facing prompt that includes the Java source,
     file metadata, and migration context.
+
+    package_context, when given, describes the target Go package and the
+    cross-package type references for project-wide conversion.
+    decimal_type selects the Go type for java.math.BigDecimal
+    (see type_mappings.DECIMAL_MAPPINGS).
+    known_kinds maps type names declared in other files to "interface",
+    "abstract" or "class" (see type_hierarchy.build_hierarchy_rules).
+    config is a loaded conversion config (see conversion_config).
+    accessors, when given, turns on idiomatic mode: it maps project class
+    names to accessors.parse_accessors results.
     """
     m = node_analysis.get("metrics", {})
 
@@ -101,6 +170,72 @@ def build_conversion_prompt(source_code, node_analysis, file_path):
         "=== FILE CONTEXT ===",
         "\n".join(context_lines),
         "",
+    ]
+
+    if package_context:
+        prompt_parts += [
+            "=== GO PACKAGE CONTEXT ===",
+            package_context,
+            "",
+        ]
+
+    hierarchy_rules = build_hierarchy_rules(source_code, known_kinds)
+    if hierarchy_rules:
+        prompt_parts += [
+            "=== TYPE HIERARCHY ===",
+            hierarchy_rules,
+            "",
+        ]
+
+    controller_rules = build_controller_rules(source_code)
+    if controller_rules:
+        prompt_parts += [
+            "=== WEB LAYER ===",
+            controller_rules,
+            "",
+        ]
+
+    if is_junit_test(source_code):
+        prompt_parts += [
+            "=== TEST CONVERSION ===",
+            JUNIT_TEST_RULES,
+        ]
+
+    type_overrides = config["typeMappings"] if config else None
+    type_rules = build_type_mapping_rules(source_code, decimal_type, type_overrides)
+    if type_rules:
+        prompt_parts += [
+            "=== TYPE MAPPINGS (MUST FOLLOW) ===",
+            type_rules,
+            "",
+        ]
+
+    naming_rules = build_naming_rules(config)
+    if naming_rules:
+        prompt_parts += [
+            "=== NAMING ===",
+            naming_rules,
+            "",
+        ]
+
+    comment_rules = build_comment_rules(source_code)
+    if comment_rules:
+        prompt_parts += [
+            "=== COMMENTS ===",
+            comment_rules,
+            "",
+        ]
+
+    if accessors is not None:
+        accessor_rules = build_accessor_rules(source_code, accessors)
+        if accessor_rules:
+            prompt_parts += [
+                "=== IDIOMATIC ACCESSORS ===",
+                accessor_rules,
+                "",
+            ]
+
+    prompt_parts += [
         "=== JAVA SOURCE CODE ===",
         source_code,
         "",
@@ -168,7 +303,8 @@ def _call_gemini_with_retry(model, prompt, max_retries=3, initial_delay=2):
     raise Exception("Unexpected error in retry logic")
 
 
-def convert_java_to_go(source_code, node_analysis, file_path):
+def convert_java_to_go(source_code, node_analysis, file_path, package_context=None, decimal_type=None,
+                       known_kinds=None, config=None, accessors=None):
     """
     Send Java source code to Gemini and get back converted Go code.
     Returns the Go source code as a string.
@@ -185,7 +321,9 @@ def convert_java_to_go(source_code, node_analysis, file_path):
         ),
     )
 
-    prompt = build_conversion_prompt(source_code, node_analysis, file_path)
+    prompt = build_conversion_prompt(
+        source_code, node_analysis, file_path, package_context, decimal_type, known_kinds, config, accessors
+    )
 
     print(f"🔄 Sending {file_path} to Gemini for Java → Go conversion...")
     response = _call_gemini_with_retry(model, prompt)
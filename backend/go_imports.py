@@ -0,0 +1,262 @@
+# backend/go_imports.py
+"""
+goimports-style import fixing for converted Go files, plus go.mod emission.
+
+Gemini output often imports packages it never uses, forgets ones it does
+(big, decimal, testify) and repeats imports across blocks. fix_imports
+rebuilds each file's import block from the package selectors actually used;
+write_go_mod then declares the third-party modules those imports need.
+"""
+import os
+import re
+import shutil
+import subprocess
+
+# 1.22 for method/wildcard ServeMux patterns used by converted controllers.
+GO_VERSION = "1.22"
+
+# Package name → import path for names Gemini commonly uses without importing.
+KNOWN_PACKAGES = {
+    "bufio": "bufio",
+    "bytes": "bytes",
+    "context": "context",
+    "errors": "errors",
+    "fmt": "fmt",
+    "io": "io",
+    "log": "log",
+    "math": "math",
+    "os": "os",
+    "regexp": "regexp",
+    "sort": "sort",
+    "strconv": "strconv",
+    "strings": "strings",
+    "sync": "sync",
+    "testing": "testing",
+    "time": "time",
+    "unicode": "unicode",
+    "big": "math/big",
+    "atomic": "sync/atomic",
+    "sql": "database/sql",
+    "json": "encoding/json",
+    "xml": "encoding/xml",
+    "http": "net/http",
+    "httptest": "net/http/httptest",
+    "filepath": "path/filepath",
+    "reflect": "reflect",
+    "slices": "slices",
+    "maps": "maps",
+    "decimal": "github.com/shopspring/decimal",
+    "assert": "github.com/stretchr/testify/assert",
+    "require": "github.com/stretchr/testify/require",
+    "uuid": "github.com/google/uuid",
+}
+
+# Module path → version written to go.mod for third-party imports.
+KNOWN_MODULE_VERSIONS = {
+    "github.com/shopspring/decimal": "v1.4.0",
+    "github.com/stretchr/testify": "v1.9.0",
+    "github.com/google/uuid": "v1.6.0",
+}
+
+IMPORT_BLOCK_RE = re.compile(r"^import\s*\((.*?)^\)\s*\n?", re.MULTILINE | re.DOTALL)
+IMPORT_LINE_RE = re.compile(r'^import\s+((?:[\w.]+\s+)?"[^"]+")[ \t]*(?://.*)?\n?', re.MULTILINE)
+IMPORT_SPEC_RE = re.compile(r'^\s*(?:(?P<alias>[\w.]+)\s+)?"(?P<path>[^"]+)"', re.MULTILINE)
+PACKAGE_CLAUSE_RE = re.compile(r"^package\s+\w+[^\n]*\n", re.MULTILINE)
+SELECTOR_RE = re.compile(r"(?<![\w.])([A-Za-z_]\w*)\.[A-Za-z_]")
+STRIP_RE = re.compile(
+    r'//[^\n]*|/\*.*?\*/|"(?:\\.|[^"\\\n])*"|`[^`]*`|\'(?:\\.|[^\'\\\n])+\'',
+    re.DOTALL,
+)
+
+FUNC_PARAMS_RE = re.compile(
+    r"\bfunc\s*(?:\((?P<recv>[^)]*)\))?\s*\w*\s*(?:\[[^\]]*\])?\((?P<params>[^)]*)\)\s*(?:\((?P<results>[^)]*)\))?"
+)
+SHORT_DECL_RE = re.compile(r"(?<![\w.])(\w+(?:\s*,\s*\w+)*)\s*:=")
+VAR_DECL_RE = re.compile(r"\b(?:var|const)\s+(\w+(?:\s*,\s*\w+)*)")
+IMPORT_PATH_ELEM_RE = re.compile(r"[A-Za-z0-9._~+-]+")
+
+
+def is_import_path(path):
+    """Whether path is a well-formed Go import path: slash-separated elements of letters, digits and -._~+."""
+    return all(
+        IMPORT_PATH_ELEM_RE.fullmatch(elem) and elem.strip(".") and not elem.endswith(".")
+        for elem in path.split("/")
+    )
+
+
+def package_name_for(import_path):
+    """Default package name for an import path (handles /vN and gopkg.in/x.vN)."""
+    parts = import_path.rstrip("/").split("/")
+    name = parts[-1]
+    if re.fullmatch(r"v\d+", name) and len(parts) > 1:
+        name = parts[-2]
+    name = re.sub(r"\.v\d+$", "", name)
+    return re.sub(r"[^\w]", "_", re.sub(r"^go-", "", name))
+
+
+def parse_imports(go_code):
+    """Return [(alias or None, path)] for every import spec in the file."""
+    specs = []
+    for block in IMPORT_BLOCK_RE.finditer(go_code):
+        specs += [(m.group("alias"), m.group("path")) for m in IMPORT_SPEC_RE.finditer(block.group(1))]
+    for line in IMPORT_LINE_RE.finditer(go_code):
+        m = IMPORT_SPEC_RE.search(line.group(1))
+        specs.append((m.group("alias"), m.group("path")))
+    return specs
+
+
+def used_selectors(go_code):
+    """Identifiers used as the left side of a selector (pkg.Name), outside strings and comments."""
+    code = STRIP_RE.sub(" ", go_code)
+    code = IMPORT_BLOCK_RE.sub("", code)
+    code = IMPORT_LINE_RE.sub("", code)
+    return set(SELECTOR_RE.findall(code))
+
+
+def declared_names(go_code):
+    """
+    Identifiers the file declares as parameters, results, receivers or
+    variables. A selector on one of these (sort.Apply() on a `sort Sorter`
+    parameter) is a local, not a package that needs importing.
+    """
+    code = STRIP_RE.sub(" ", go_code)
+    names = set()
+    for m in FUNC_PARAMS_RE.finditer(code):
+        for group in ("recv", "params", "results"):
+            pieces = [p.split() for p in (m.group(group) or "").split(",") if p.strip()]
+            # Go parameter lists are either all named or all unnamed.
+            if any(len(p) > 1 for p in pieces):
+                names.update(p[0] for p in pieces if re.fullmatch(r"[A-Za-z_]\w*", p[0]))
+    for m in list(SHORT_DECL_RE.finditer(code)) + list(VAR_DECL_RE.finditer(code)):
+        names.update(n.strip() for n in m.group(1).split(","))
+    return names
+
+
+def fix_imports(go_code, local_packages=None, module_name=None):
+    """
+    Rebuild the import block: drop unused and duplicate imports, add missing
+    ones for KNOWN_PACKAGES and local_packages ({package name: import path})
+    unless the name is declared locally, and group standard library, then
+    third-party, then the module's own imports (those under module_name or in
+    local_packages).
+    """
+    known = dict(KNOWN_PACKAGES)
+    known.update(local_packages or {})
+
+    existing = parse_imports(go_code)
+    used = used_selectors(go_code)
+
+    chosen = {}  # path -> alias
+    for alias, path in existing:
+        if alias in ("_", "."):
+            chosen[path] = alias
+        elif (alias or package_name_for(path)) in used:
+            chosen.setdefault(path, alias)
+
+    imported_names = {alias or package_name_for(path) for path, alias in chosen.items()}
+    for name in sorted(used - imported_names - declared_names(go_code)):
+        if name in known:
+            chosen.setdefault(known[name], None)
+
+    body = IMPORT_BLOCK_RE.sub("", go_code)
+    body = IMPORT_LINE_RE.sub("", body)
+
+    clause = PACKAGE_CLAUSE_RE.search(body)
+    if not clause:
+        return go_code
+
+    head = body[: clause.end()]
+    rest = body[clause.end():].lstrip("\n")
+    local = {p for p in chosen if p in (local_packages or {}).values() or _in_module(p, module_name)}
+    block = _format_import_block(chosen, local)
+    if not block:
+        return f"{head}\n{rest}"
+    return f"{head}\n{block}\n\n{rest}"
+
+
+def _in_module(import_path, module_name):
+    return bool(module_name) and (import_path == module_name or import_path.startswith(module_name + "/"))
+
+
+def _format_import_block(chosen, local=()):
+    """
+    The import declaration for chosen ({path: alias}). Paths in local are the
+    module's own packages and get their own group, since a module name need
+    not contain a dot.
+    """
+    if not chosen:
+        return ""
+
+    def spec(path):
+        alias = chosen[path]
+        return f'{alias} "{path}"' if alias else f'"{path}"'
+
+    own = sorted(p for p in chosen if p in local)
+    std = sorted(p for p in chosen if p not in local and "." not in p.split("/")[0])
+    other = sorted(p for p in chosen if p not in local and p not in std)
+
+    if len(chosen) == 1:
+        return f"import {spec(next(iter(chosen)))}"
+
+    groups = [[f"\t{spec(p)}" for p in group] for group in (std, other, own) if group]
+    return "import (\n" + "\n\n".join("\n".join(g) for g in groups) + "\n)"
+
+
+def module_for(import_path, module_name):
+    """Return the third-party module an import belongs to, or None for std/local."""
+    if _in_module(import_path, module_name):
+        return None
+    first = import_path.split("/")[0]
+    if "." not in first:
+        return None
+    for module in KNOWN_MODULE_VERSIONS:
+        if import_path == module or import_path.startswith(module + "/"):
+            return module
+    parts = import_path.split("/")
+    if first in ("github.com", "gitlab.com", "bitbucket.org", "golang.org"):
+        return "/".join(parts[:3])
+    return import_path
+
+
+def write_go_mod(output_root, module_name, go_files):
+    """
+    Write go.mod declaring the third-party modules imported by go_files, then
+    run `go mod tidy` and `gofmt` when a Go toolchain is installed so the
+    module builds straight away. Returns the sorted list of required modules.
+    """
+    required = set()
+    for path in go_files:
+        with open(path, "r") as f:
+            for _, import_path in parse_imports(f.read()):
+                module = module_for(import_path, module_name)
+                if module:
+                    required.add(module)
+
+    lines = [f"module {module_name}", "", f"go {GO_VERSION}"]
+    pinned = sorted(m for m in required if m in KNOWN_MODULE_VERSIONS)
+    if pinned:
+        lines += ["", "require ("] + [f"\t{m} {KNOWN_MODULE_VERSIONS[m]}" for m in pinned] + [")"]
+
+    go_mod_path = os.path.join(output_root, "go.mod")
+    with open(go_mod_path, "w") as f:
+        f.write("\n".join(lines) + "\n")
+    print(f"📦 Wrote {go_mod_path} ({len(required)} dependencies)")
+
+    unpinned = sorted(required - set(pinned))
+    if unpinned:
+        print(f"⚠️  No pinned version for {', '.join(unpinned)}; go mod tidy will resolve the latest")
+
+    _run_go_tool(["go", "mod", "tidy"], output_root)
+    _run_go_tool(["gofmt", "-w", "."], output_root)
+    return sorted(required)
+
+
+def _run_go_tool(cmd, cwd):
+    if not shutil.which(cmd[0]):
+        print(f"⚠️  {cmd[0]} not found on PATH; run `{' '.join(cmd)}` in {cwd} manually")
+        return False
+    result = subprocess.run(cmd, cwd=cwd, capture_output=True, text=True)
+    if result.returncode != 0:
+        print(f"⚠️  `{' '.join(cmd)}` failed: {result.stderr.strip()}")
+        return False
+    return True
@@ -0,0 +1,171 @@
+# backend/go_verify.py
+"""
+Post-conversion verification: run `go build` and `go test` on the emitted
+module and report a pass/fail result per Go package.
+
+Compiler errors and failing tests are reduced to Go file/line references;
+ConversionReport.add_verification maps those back to the Java files they
+were converted from, so migrators see breakage next to its source.
+"""
+import json
+import os
+import re
+import shutil
+import subprocess
+from collections import defaultdict
+
+DEFAULT_TIMEOUT = 600
+
+LOCATION_RE = re.compile(r"^\s*(?:\./)?(?P<file>[\w./\\-]+\.go):(?P<line>\d+)(?::\d+)?: (?P<message>.*)$")
+
+
+def _run(cmd, cwd, timeout):
+    return subprocess.run(cmd, cwd=cwd, capture_output=True, text=True, timeout=timeout)
+
+
+def _list_packages(output_root, timeout):
+    """Import path → directory for every package in the module (go list -e survives broken code)."""
+    result = _run(["go", "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}", "./..."], output_root, timeout)
+    packages = {}
+    for line in result.stdout.splitlines():
+        import_path, _, directory = line.partition("\t")
+        if import_path and directory:
+            packages[import_path] = directory
+    return packages
+
+
+def parse_locations(output, base_dir):
+    """[{"goFile" (absolute), "line", "message"}] for file:line: messages in compiler or test output."""
+    found = []
+    for line in output.splitlines():
+        m = LOCATION_RE.match(line)
+        if m:
+            found.append({
+                "goFile": os.path.normpath(os.path.join(base_dir, m.group("file"))),
+                "line": int(m.group("line")),
+                "message": m.group("message").strip(),
+            })
+    return found
+
+
+def _package_path(value):
+    """Import path without the test-variant suffix: "vt/a [vt/a.test]" → "vt/a"."""
+    return re.sub(r" \[[^\]]*\]$", "", value) if value else value
+
+
+def _test_file(directory, test_name):
+    """The _test.go file in directory that declares the top-level test, or None."""
+    func = test_name.split("/")[0]
+    pattern = re.compile(rf"^func\s+{re.escape(func)}\s*\(", re.MULTILINE)
+    for name in sorted(os.listdir(directory)):
+        if name.endswith("_test.go"):
+            path = os.path.join(directory, name)
+            with open(path, "r", encoding="utf-8", errors="replace") as f:
+                if pattern.search(f.read()):
+                    return path
+    return None
+
+
+def verify_module(output_root, timeout=DEFAULT_TIMEOUT):
+    """
+    Build and test the Go module at output_root. Returns
+    {"status": "passed" | "failed" | "skipped" | "error", "reason", "packages": [...]}
+    where each package is {"importPath", "dir", "build", "tests", "blockedBy", "errors", "failedTests"}.
+    build is "passed", "failed" or "blocked" (a dependency failed to build);
+    tests is "passed", "failed", "none" (no test files) or "not run".
+    """
+    if not shutil.which("go"):
+        return {"status": "skipped", "reason": "go not found on PATH", "packages": []}
+
+    try:
+        packages = _list_packages(output_root, timeout)
+        build = _run(["go", "build", "./..."], output_root, timeout)
+        test = _run(["go", "test", "-json", "./..."], output_root, timeout)
+    except subprocess.TimeoutExpired as e:
+        return {"status": "error", "reason": f"`{' '.join(e.cmd)}` timed out after {timeout}s", "packages": []}
+
+    dirs = {directory: import_path for import_path, directory in packages.items()}
+    results = {
+        import_path: {
+            "importPath": import_path,
+            "dir": os.path.relpath(directory, output_root),
+            "build": "passed",
+            "tests": "none",
+            "blockedBy": None,
+            "errors": [],
+            "failedTests": [],
+        }
+        for import_path, directory in sorted(packages.items())
+    }
+
+    def package_of(go_file):
+        return dirs.get(os.path.dirname(go_file))
+
+    seen_errors = set()
+
+    def add_error(error):
+        key = (error["goFile"], error["line"], error["message"])
+        pkg = package_of(error["goFile"])
+        if pkg in results and key not in seen_errors:
+            seen_errors.add(key)
+            results[pkg]["errors"].append(error)
+            results[pkg]["build"] = "failed"
+
+    for error in parse_locations(build.stderr, output_root):
+        add_error(error)
+
+    test_output = defaultdict(list)  # (package, test) -> output lines
+    for line in test.stdout.splitlines():
+        try:
+            event = json.loads(line)
+        except ValueError:
+            continue
+        pkg = _package_path(event.get("Package") or event.get("ImportPath"))
+        if pkg not in results:
+            continue
+        action, name = event.get("Action"), event.get("Test")
+
+        if action in ("build-output", "output") and not name:
+            # Test-only compile errors (in _test.go files) only show up here.
+            for error in parse_locations(event.get("Output", ""), output_root):
+                add_error(error)
+        elif action == "output" and name:
+            test_output[(pkg, name)].append(event.get("Output", ""))
+        elif action == "fail" and name:
+            output = "".join(test_output.pop((pkg, name), []))
+            directory = packages[pkg]
+            located = parse_locations(output, directory)
+            results[pkg]["failedTests"].append({
+                "name": name,
+                "goFile": located[0]["goFile"] if located else _test_file(directory, name),
+                "line": located[0]["line"] if located else None,
+                "message": located[0]["message"] if located else None,
+                "output": output.strip()[-2000:],
+            })
+        elif action in ("pass", "fail", "skip") and not name:
+            failed_build = _package_path(event.get("FailedBuild"))
+            if failed_build:
+                # FailedBuild names the package that broke, which may be a dependency.
+                if failed_build == pkg or results[pkg]["errors"]:
+                    results[pkg]["build"] = "failed"
+                else:
+                    results[pkg]["build"] = "blocked"
+                    results[pkg]["blockedBy"] = failed_build
+                results[pkg]["tests"] = "not run"
+            elif action == "skip":
+                results[pkg]["tests"] = "none"
+            else:
+                results[pkg]["tests"] = "passed" if action == "pass" else "failed"
+
+    # A parent test fails whenever a subtest does; keep only the innermost failures.
+    for result in results.values():
+        names = {t["name"] for t in result["failedTests"]}
+        result["failedTests"] = [
+            t for t in result["failedTests"] if not any(n.startswith(t["name"] + "/") for n in names)
+        ]
+
+    failed = any(r["build"] != "passed" or r["tests"] in ("failed", "not run") for r in results.values())
+    if build.returncode != 0 and not failed:
+        # e.g. go.mod problems, which are not tied to a package.
+        return {"status": "failed", "reason": build.stderr.strip()[-2000:], "packages": list(results.values())}
+    return {"status": "failed" if failed else "passed", "reason": None, "packages": list(results.values())}
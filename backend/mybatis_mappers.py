@@ -0,0 +1,850 @@
+# backend/mybatis_mappers.py
+"""
+MyBatis mapper XML → database/sql repository code.
+
+For every <mapper namespace="pkg.FooMapper"> whose Java interface is part of
+the converted tree, this stage:
+
+1. derives the exact Go method set of the FooMapper interface from the Java
+   interface, so Gemini emits a matching Go interface;
+2. generates foomapper_sql.go with a SQLFooMapper implementing it, one
+   method per <select>/<insert>/<update>/<delete>, including dynamic SQL
+   (<if>, <where>, <set>, <trim>, <foreach>, <choose>, <bind>, <include>);
+3. writes a per-package mybatis_support.go with the shared scan/bind helpers.
+
+The generation is deterministic (no Gemini) so the SQL stays exactly as written.
+"""
+import re
+import xml.etree.ElementTree as ET
+
+from type_mappings import resolve_type_mappings
+
+SCALAR_GO_TYPES = {
+    "String": "string", "char": "string", "Character": "string",
+    "int": "int", "Integer": "int", "short": "int", "Short": "int",
+    "long": "int64", "Long": "int64",
+    "double": "float64", "Double": "float64", "float": "float64", "Float": "float64",
+    "boolean": "bool", "Boolean": "bool",
+    "byte[]": "[]byte", "Object": "any",
+}
+PRIMITIVE_TYPES = {"int", "long", "short", "byte", "double", "float", "boolean", "char"}
+WRAPPER_TYPES = {"Integer", "Long", "Short", "Byte", "Double", "Float", "Boolean", "Character"}
+LIST_TYPES = {"List", "Collection", "Set", "ArrayList", "Iterable"}
+
+PARAM_RE = re.compile(r"([#$])\{([^}]+)\}")
+INTERFACE_METHOD_RE = re.compile(
+    r"^\s*(?:public\s+|abstract\s+)*(?P<ret>[\w<>\[\],.?\s]+?)\s+(?P<name>\w+)\s*\((?P<params>(?:[^()]|\([^()]*\))*)\)\s*"
+    r"(?:throws\s+[\w.,\s]+)?;",
+    re.MULTILINE,
+)
+PARAM_ANNOTATION_RE = re.compile(r'@Param\s*\(\s*(?:value\s*=\s*)?"(\w+)"\s*\)')
+FIELD_RE = re.compile(
+    r"^\s*(?:private|protected|public)\s+(?!static\b)(?:final\s+)?(?P<type>[\w<>\[\],.?\s]+?)\s+(?P<name>\w+)\s*(?:=[^;]*)?;",
+    re.MULTILINE,
+)
+# Identifiers the generated method bodies declare or reference; Java parameters
+# with these names are renamed so they are not shadowed or redeclared.
+GENERATED_NAMES = {
+    "m", "q", "args", "rows", "err", "res", "n", "item", "result", "results",
+    "bind", "trimSQL", "nullable", "scanRow", "strings", "fmt", "errors", "sql",
+}
+GENERATED_NAME_RE = re.compile(r"(?:b|s|i|item|bound)\d+$")
+GO_KEYWORDS = {
+    "break", "case", "chan", "const", "continue", "default", "defer", "else",
+    "fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+    "map", "package", "range", "return", "select", "struct", "switch", "type", "var",
+}
+TEST_TOKEN_RE = re.compile(
+    r"\s*(?:(?P<op>==|!=|<=|>=|&&|\|\||<|>|\(|\)|!|\+)|(?P<str>'[^']*'|\"[^\"]*\")|"
+    r"(?P<num>\d+(?:\.\d+)?)|(?P<ident>[A-Za-z_][\w.]*(?:\(\))?))"
+)
+
+
+# ─── Parsing ──────────────────────────────────────────────────
+
+def parse_mapper_xml(path):
+    """Return {"namespace", "statements", "resultMaps", "fragments"} or None if not a mapper."""
+    try:
+        root = ET.parse(path).getroot()
+    except ET.ParseError as e:
+        print(f"⚠️  Skipping unparsable mapper XML {path}: {e}")
+        return None
+    if root.tag != "mapper" or not root.get("namespace"):
+        return None
+
+    mapper = {
+        "path": path,
+        "namespace": root.get("namespace"),
+        "statements": {},
+        "resultMaps": {},
+        "fragments": {},
+    }
+    for el in root:
+        if el.tag in ("select", "insert", "update", "delete"):
+            mapper["statements"][el.get("id")] = el
+        elif el.tag == "sql":
+            mapper["fragments"][el.get("id")] = el
+        elif el.tag == "resultMap":
+            mapper["resultMaps"][el.get("id")] = {
+                "type": el.get("type", ""),
+                "mappings": [(c.get("column"), c.get("property")) for c in el
+                             if c.tag in ("id", "result") and c.get("column") and c.get("property")],
+            }
+    return mapper
+
+
+def _split_params(params):
+    parts, depth, current = [], 0, ""
+    for ch in params:
+        if ch == "<":
+            depth += 1
+        elif ch == ">":
+            depth -= 1
+        if ch == "," and depth == 0:
+            parts.append(current)
+            current = ""
+        else:
+            current += ch
+    parts.append(current)
+    return [p.strip() for p in parts if p.strip()]
+
+
+def parse_mapper_interface(source_code):
+    """Return [{"name", "returnType", "params": [{"type", "name", "paramName"}]}] for a Java mapper interface."""
+    body_start = source_code.find("{")
+    body = source_code[body_start + 1:] if body_start >= 0 else source_code
+    methods = []
+    for m in INTERFACE_METHOD_RE.finditer(body):
+        params = []
+        for raw in _split_params(m.group("params")):
+            annotation = PARAM_ANNOTATION_RE.search(raw)
+            decl = re.sub(r"@\w+(?:\([^)]*\))?", "", raw).replace("final ", "").split()
+            if len(decl) < 2:
+                continue
+            params.append({
+                "type": " ".join(decl[:-1]),
+                "name": decl[-1],
+                "paramName": annotation.group(1) if annotation else None,
+            })
+        ret = re.sub(r"@\w+(?:\([^)]*\))?", "", m.group("ret")).strip().split()[-1]
+        methods.append({"name": m.group("name"), "returnType": ret, "params": params})
+    return methods
+
+
+def parse_fields(source_code):
+    """Return {field name: java type} for the instance fields of a Java class."""
+    return {m.group("name"): m.group("type").strip() for m in FIELD_RE.finditer(source_code)}
+
+
+# ─── Type mapping ─────────────────────────────────────────────
+
+def _generic_args(java_type):
+    m = re.match(r"[\w.]+\s*<(.*)>$", java_type.strip())
+    return _split_params(m.group(1)) if m else []
+
+
+def _raw(java_type):
+    return re.sub(r"<.*", "", java_type).strip().split(".")[-1]
+
+
+def exported(name):
+    return name[:1].upper() + name[1:]
+
+
+class TypeMapper:
+    """
+    Maps Java types to Go type expressions, qualifying project types via
+    qualify(java_type). type_mappings is a type_mappings.resolve_type_mappings
+    result, so BigDecimal, dates and config overrides match the prompt; the
+    imports they need are collected in self.imports.
+    """
+
+    def __init__(self, qualify, type_mappings=None):
+        self.qualify = qualify
+        self.mappings = {
+            java_type.split(".")[-1]: mapping
+            for java_type, mapping in (type_mappings or resolve_type_mappings()).items()
+        }
+        self.imports = set()
+
+    def go_type(self, java_type):
+        raw = _raw(java_type)
+        mapping = self.mappings.get(raw)
+        if mapping:
+            if mapping.get("import"):
+                self.imports.add(mapping["import"])
+            return mapping["goType"]
+        if java_type.strip() in SCALAR_GO_TYPES:
+            return SCALAR_GO_TYPES[java_type.strip()]
+        if raw in SCALAR_GO_TYPES:
+            return SCALAR_GO_TYPES[raw]
+        if raw in LIST_TYPES:
+            args = _generic_args(java_type)
+            return "[]" + (self.go_type(args[0]) if args else "any")
+        if raw == "Map":
+            args = _generic_args(java_type)
+            if len(args) == 2:
+                return f"map[{self.go_type(args[0])}]{self.go_type(args[1])}"
+            return "map[string]any"
+        if java_type.endswith("[]"):
+            return "[]" + self.go_type(java_type[:-2])
+        return self.qualify(raw) or "any"
+
+    def is_struct(self, java_type):
+        """True for project classes, which are passed and scanned as *T."""
+        qualified = self.qualify(_raw(java_type))
+        return bool(qualified) and qualified.startswith("*")
+
+    def struct_name(self, java_type):
+        """Go struct expression without the pointer, e.g. domain.Account."""
+        return self.go_type(java_type).lstrip("*")
+
+
+def zero_value(go_type):
+    if go_type.startswith(("*", "[]", "map[")) or go_type == "any":
+        return "nil"
+    if go_type == "string":
+        return '""'
+    if go_type in ("int", "int64", "float64"):
+        return "0"
+    if go_type == "bool":
+        return "false"
+    return f"{go_type}{{}}"
+
+
+def _null_check(expr, go_type, is_null):
+    """Go expression for `expr == null` (is_null) or `expr != null` by Go type."""
+    if go_type.startswith(("*", "[]", "map[")) or go_type == "any":
+        return f"{expr} {'==' if is_null else '!='} nil"
+    if go_type == "string":
+        return f'{expr} {"==" if is_null else "!="} ""'
+    if go_type in ("int", "int64", "float64"):
+        return f"{expr} {'==' if is_null else '!='} 0"
+    if go_type == "bool":
+        return "false" if is_null else "true"
+    return f"{'' if is_null else '!'}{expr}.IsZero()"
+
+
+# ─── Code generation ──────────────────────────────────────────
+
+class MapperGenerator:
+    def __init__(self, mapper, methods, go_package, types, type_fields, mapper_name, hidden_fields=None):
+        self.mapper = mapper
+        self.methods = methods
+        self.go_package = go_package
+        self.types = types
+        self.type_fields = type_fields  # java class name -> {field: java type}
+        self.hidden_fields = hidden_fields or {}  # java class name -> fields only reachable via X()
+        self.name = mapper_name
+        self.struct = f"SQL{mapper_name}"
+        self.warnings = []
+        self.column_funcs = {}  # (java type, resultMap id) -> func name
+        self._counter = 0
+        for method in methods:
+            _assign_go_names(method["params"])
+
+    # Signatures ------------------------------------------------
+
+    def go_params(self, method):
+        return ", ".join(f"{p['goName']} {self.types.go_type(p['type'])}" for p in method["params"])
+
+    def go_results(self, method):
+        ret = method["returnType"]
+        if ret == "void":
+            return "error"
+        return f"({self.types.go_type(ret)}, error)"
+
+    def signature(self, method):
+        return f"{exported(method['name'])}({self.go_params(method)}) {self.go_results(method)}"
+
+    def interface_methods(self):
+        return [self.signature(m) for m in self.methods]
+
+    # Generation ------------------------------------------------
+
+    def generate(self):
+        body = []
+        for method in self.methods:
+            body += self.generate_method(method)
+            body.append("")
+        for key, func_name in self.column_funcs.items():
+            body += self.generate_column_func(key, func_name)
+            body.append("")
+
+        out = [
+            "// Code generated by Shadow-Code from MyBatis mapper "
+            f"{self.mapper['namespace']}. DO NOT EDIT.",
+            "",
+            f"package {self.go_package}",
+            "",
+        ]
+        # Mapped types (custom decimals, config overrides) may live in packages
+        # fix_imports cannot guess; it adds the standard ones and drops unused ones.
+        if self.types.imports:
+            out += [f'import "{path}"' for path in sorted(self.types.imports)] + [""]
+        out += [
+            f"// {self.struct} implements {self.name} with database/sql, one method per mapped statement.",
+            f"type {self.struct} struct {{",
+            "\tdb DBTX",
+            "}",
+            "",
+            f"// New{self.struct} returns an implementation of {self.name} backed by db (a *sql.DB or *sql.Tx).",
+            f"func New{self.struct}(db DBTX) *{self.struct} {{",
+            f"\treturn &{self.struct}{{db: db}}",
+            "}",
+            "",
+            f"var _ {self.name} = (*{self.struct})(nil)",
+            "",
+        ]
+        return "\n".join(out + body).rstrip() + "\n"
+
+    def generate_method(self, method):
+        header = f"func (m *{self.struct}) {self.signature(method)} {{"
+        statement = self.mapper["statements"].get(method["name"])
+        ret = method["returnType"]
+        ret_go = None if ret == "void" else self.types.go_type(ret)
+
+        if statement is None:
+            self.warnings.append(f"{self.name}.{method['name']}: no mapped statement in XML (annotation SQL?)")
+            fail = "return " + (f"{zero_value(ret_go)}, " if ret_go else "")
+            return [
+                header,
+                f'\t{fail}errors.New("{self.name}.{method["name"]}: no mapped statement")',
+                "}",
+            ]
+
+        scope = self.method_scope(method)
+        body = ["\tvar q strings.Builder", "\tvar args []any"]
+        body += _merge_writes(self.emit_children(statement, "q", scope, 1))
+        body += self.emit_execution(statement, method, ret, ret_go)
+        return [header] + body + ["}"]
+
+    def method_scope(self, method):
+        """Names usable in #{...} / test="..." → (Go expression, Java type)."""
+        scope = {}
+        params = method["params"]
+        for i, p in enumerate(params, start=1):
+            entry = (p["goName"], p["type"])
+            scope[p["paramName"] or p["name"]] = entry
+            scope[p["name"]] = entry
+            scope[f"param{i}"] = entry
+        if len(params) == 1:
+            scope["_parameter"] = (params[0]["goName"], params[0]["type"])
+            self._single = params[0]
+        else:
+            self._single = None
+        if len(params) == 1 and _raw(params[0]["type"]) in LIST_TYPES:
+            scope.setdefault("list", (params[0]["goName"], params[0]["type"]))
+            scope.setdefault("collection", (params[0]["goName"], params[0]["type"]))
+        return scope
+
+    def resolve(self, path, scope):
+        """Resolve a dotted property path to (Go expression, Java type), or None."""
+        parts = path.strip().split(".")
+        if parts[0] in scope:
+            expr, java_type = scope[parts[0]]
+            rest = parts[1:]
+        elif self._single is not None and self.types.is_struct(self._single["type"]):
+            expr, java_type = self._single["goName"], self._single["type"]
+            rest = parts
+        elif self._single is not None and len(parts) == 1:
+            # A lone simple parameter can be referenced by any name.
+            return self._single["goName"], self._single["type"]
+        else:
+            return None
+
+        for prop in rest:
+            fields = self.type_fields.get(_raw(java_type), {})
+            getter = "()" if prop in self.hidden_fields.get(_raw(java_type), ()) else ""
+            expr = f"{expr}.{exported(prop)}{getter}"
+            java_type = fields.get(prop, "Object")
+        return expr, java_type
+
+    def _next(self, prefix):
+        self._counter += 1
+        return f"{prefix}{self._counter}"
+
+    def emit_text(self, text, builder, scope, depth):
+        if not text:
+            return []
+        text = re.sub(r"\s+", " ", text)
+        if not text.strip():
+            return [] if not text else [f'{_tabs(depth)}{builder}.WriteString(" ")']
+        lines = []
+        pos = 0
+        for m in PARAM_RE.finditer(text):
+            literal = text[pos:m.start()]
+            if literal:
+                lines.append(f"{_tabs(depth)}{builder}.WriteString({_go_string(literal)})")
+            prop = m.group(2).split(",")[0].strip()
+            resolved = self.resolve(prop, scope)
+            if resolved is None:
+                self.warnings.append(f"{self.name}: cannot resolve parameter {m.group(0)}")
+                expr = f"nil /* TODO: {m.group(0)} */"
+            else:
+                expr = resolved[0]
+            if m.group(1) == "#":
+                lines.append(f"{_tabs(depth)}bind(&{builder}, &args, {expr})")
+            else:
+                self.warnings.append(f"{self.name}: {m.group(0)} is spliced into SQL text (injection risk)")
+                lines.append(f"{_tabs(depth)}{builder}.WriteString(fmt.Sprint({expr}))")
+            pos = m.end()
+        if text[pos:]:
+            lines.append(f"{_tabs(depth)}{builder}.WriteString({_go_string(text[pos:])})")
+        return lines
+
+    def emit_children(self, el, builder, scope, depth):
+        lines = self.emit_text(el.text, builder, scope, depth)
+        for child in el:
+            lines += self.emit_element(child, builder, scope, depth)
+            lines += self.emit_text(child.tail, builder, scope, depth)
+        return lines
+
+    def emit_element(self, el, builder, scope, depth):
+        t = _tabs(depth)
+        tag = el.tag
+
+        if tag == "include":
+            refid = (el.get("refid") or "").split(".")[-1]
+            fragment = self.mapper["fragments"].get(refid)
+            if fragment is None:
+                self.warnings.append(f"{self.name}: unknown <include refid=\"{refid}\">")
+                return [f"{t}// TODO: missing <sql id=\"{refid}\"> fragment"]
+            return self.emit_children(fragment, builder, scope, depth)
+
+        if tag == "if":
+            cond = self.translate_test(el.get("test", ""), scope)
+            if cond == "true":
+                # e.g. `x != null` on a primitive: always included.
+                return self.emit_children(el, builder, scope, depth)
+            return [f"{t}if {cond} {{"] + self.emit_children(el, builder, scope, depth + 1) + [f"{t}}}"]
+
+        if tag == "choose":
+            lines, first = [], True
+            for branch in el:
+                if branch.tag == "when":
+                    cond = self.translate_test(branch.get("test", ""), scope)
+                    lines.append(f"{t}{'if' if first else '} else if'} {cond} {{")
+                    first = False
+                elif branch.tag == "otherwise":
+                    lines.append(f"{t}}} else {{" if not first else f"{t}{{")
+                    first = False
+                else:
+                    continue
+                lines += self.emit_children(branch, builder, scope, depth + 1)
+            if lines:
+                lines.append(f"{t}}}")
+            return lines
+
+        if tag in ("where", "set", "trim"):
+            if tag == "where":
+                prefix, suffix, prefix_overrides, suffix_overrides = "WHERE", "", "AND |OR ", ""
+            elif tag == "set":
+                prefix, suffix, prefix_overrides, suffix_overrides = "SET", "", "", ","
+            else:
+                prefix = el.get("prefix", "")
+                suffix = el.get("suffix", "")
+                prefix_overrides = el.get("prefixOverrides", "")
+                suffix_overrides = el.get("suffixOverrides", "")
+            inner = self._next("b")
+            trimmed = self._next("s")
+            lines = [f"{t}{{", f"{t}\tvar {inner} strings.Builder"]
+            lines += self.emit_children(el, inner, scope, depth + 1)
+            lines += [
+                f"{t}\tif {trimmed} := trimSQL({inner}.String(), {_go_string(prefix_overrides)}, "
+                f"{_go_string(suffix_overrides)}); {trimmed} != \"\" {{",
+                f"{t}\t\t{builder}.WriteString({_go_string(' ' + prefix + ' ' if prefix else ' ')})",
+                f"{t}\t\t{builder}.WriteString({trimmed})",
+            ]
+            if suffix:
+                lines.append(f"{t}\t\t{builder}.WriteString({_go_string(' ' + suffix + ' ')})")
+            lines += [f"{t}\t}}", f"{t}}}"]
+            return lines
+
+        if tag == "foreach":
+            collection = self.resolve(el.get("collection", "list"), scope)
+            if collection is None:
+                self.warnings.append(f"{self.name}: cannot resolve foreach collection {el.get('collection')}")
+                return [f"{t}// TODO: <foreach collection=\"{el.get('collection')}\">"]
+            coll_expr, coll_type = collection
+            elem_args = _generic_args(coll_type)
+            elem_type = elem_args[0] if elem_args else "Object"
+
+            item_var = self._next("item")
+            index_var = self._next("i")
+            inner_scope = dict(scope)
+            if el.get("item"):
+                inner_scope[el.get("item")] = (item_var, elem_type)
+            if el.get("index"):
+                inner_scope[el.get("index")] = (index_var, "int")
+
+            body = self.emit_children(el, builder, inner_scope, depth + 1)
+            separator = el.get("separator")
+            uses_index = bool(separator) or any(re.search(rf"\b{index_var}\b", line) for line in body)
+            uses_item = any(re.search(rf"\b{item_var}\b", line) for line in body)
+
+            lines = []
+            if el.get("open"):
+                lines.append(f"{t}{builder}.WriteString({_go_string(el.get('open'))})")
+            lines.append(f"{t}for {index_var if uses_index else '_'}, {item_var if uses_item else '_'} := range {coll_expr} {{")
+            if separator:
+                lines.append(f"{t}\tif {index_var} > 0 {{")
+                lines.append(f"{t}\t\t{builder}.WriteString({_go_string(separator)})")
+                lines.append(f"{t}\t}}")
+            lines += body
+            lines.append(f"{t}}}")
+            if el.get("close"):
+                lines.append(f"{t}{builder}.WriteString({_go_string(el.get('close'))})")
+            return lines
+
+        if tag == "bind":
+            var = self._next("bound")
+            value = self.translate_test(el.get("value", ""), scope)
+            scope[el.get("name")] = (var, "String")
+            return [f"{t}{var} := {value}"]
+
+        if tag == "selectKey":
+            self.warnings.append(f"{self.name}: <selectKey> not converted; fetch generated keys explicitly")
+            return [f"{t}// TODO: <selectKey> ({(el.text or '').strip()[:60]})"]
+
+        self.warnings.append(f"{self.name}: unsupported element <{tag}>")
+        return [f"{t}// TODO: unsupported <{tag}>"]
+
+    def translate_test(self, test, scope):
+        """Translate an OGNL test/bind expression to Go, or a TODO-marked `true` when it cannot."""
+        tokens = []
+        pos = 0
+        test = test.strip()
+        while pos < len(test):
+            m = TEST_TOKEN_RE.match(test, pos)
+            if not m or m.end() == pos:
+                return self._untranslatable(test)
+            pos = m.end()
+            if m.group("op"):
+                tokens.append(("op", m.group("op")))
+            elif m.group("str") is not None:
+                tokens.append(("lit", _go_string(m.group("str")[1:-1]), "string"))
+            elif m.group("num"):
+                tokens.append(("lit", m.group("num"), "number"))
+            else:
+                ident = m.group("ident")
+                lowered = ident.lower()
+                if lowered in ("and", "or"):
+                    tokens.append(("op", "&&" if lowered == "and" else "||"))
+                elif lowered == "not":
+                    tokens.append(("op", "!"))
+                elif lowered == "null":
+                    tokens.append(("null",))
+                elif lowered in ("true", "false"):
+                    tokens.append(("lit", lowered, "bool"))
+                else:
+                    suffix = None
+                    for method in (".size()", ".length()", ".isEmpty()"):
+                        if ident.endswith(method):
+                            ident, suffix = ident[: -len(method)], method
+                    resolved = self.resolve(ident, scope)
+                    if resolved is None:
+                        return self._untranslatable(test)
+                    expr, java_type = resolved
+                    if suffix in (".size()", ".length()"):
+                        tokens.append(("val", f"len({expr})", "int", "int"))
+                    elif suffix == ".isEmpty()":
+                        tokens.append(("val", f"len({expr}) == 0", "bool", "boolean"))
+                    else:
+                        tokens.append(("val", expr, self.types.go_type(java_type), java_type))
+
+        out = []
+
+        def add_condition(cond):
+            # `x != null and x != ''` collapses to the same Go check twice; keep one.
+            if len(out) >= 2 and out[-1] in ("&&", "||") and out[-2] == cond and (
+                    len(out) == 2 or out[-3] in ("&&", "||", "(")):
+                out.pop()
+            else:
+                out.append(cond)
+
+        i = 0
+        while i < len(tokens):
+            tok = tokens[i]
+            nxt = tokens[i + 1] if i + 1 < len(tokens) else None
+            after = tokens[i + 2] if i + 2 < len(tokens) else None
+            if tok[0] == "val" and nxt and nxt[0] == "op" and nxt[1] in ("==", "!=") and after and after[0] == "null":
+                add_condition(self.null_check(tok, nxt[1] == "=="))
+                i += 3
+                continue
+            if tok[0] == "null" and nxt and nxt[0] == "op" and nxt[1] in ("==", "!=") and after and after[0] == "val":
+                add_condition(self.null_check(after, nxt[1] == "=="))
+                i += 3
+                continue
+            if tok[0] == "val" and nxt and nxt[0] == "op" and nxt[1] in ("==", "!=") and after == ("lit", '""', "string"):
+                add_condition(f'{tok[1]} {nxt[1]} ""')
+                i += 3
+                continue
+            if tok[0] == "null":
+                return self._untranslatable(test)
+            out.append(tok[1])
+            i += 1
+        return " ".join(out).replace("( ", "(").replace(" )", ")").replace("! ", "!")
+
+    def null_check(self, token, is_null):
+        """Go condition for `value == null` (is_null) or `value != null` on a ("val", expr, go type, java type) token."""
+        _, expr, go_type, java_type = token
+        java_type = java_type.strip()
+        if java_type in PRIMITIVE_TYPES:
+            # A Java primitive is never null.
+            return "false" if is_null else "true"
+        if java_type in WRAPPER_TYPES:
+            self.warnings.append(f"{self.name}: {expr} {'==' if is_null else '!='} null on {java_type} "
+                                 f"approximated as a zero-value check; a real 0/false is treated as null")
+        return _null_check(expr, go_type, is_null)
+
+    def _untranslatable(self, test):
+        self.warnings.append(f"{self.name}: could not translate test \"{test}\"")
+        return f"true /* TODO: {test} */"
+
+    def emit_execution(self, statement, method, ret, ret_go):
+        query = "strings.TrimSpace(q.String())"
+        if statement.tag != "select":
+            if ret_go is None:
+                return [f"\t_, err := m.db.Exec({query}, args...)", "\treturn err"]
+            return [
+                f"\tres, err := m.db.Exec({query}, args...)",
+                "\tif err != nil {",
+                f"\t\treturn {zero_value(ret_go)}, err",
+                "\t}",
+                "\tn, err := res.RowsAffected()",
+                f"\treturn {ret_go}(n), err",
+            ]
+
+        if ret_go is None:
+            return [f"\t_, err := m.db.Exec({query}, args...)", "\treturn err"]
+
+        is_list = ret_go.startswith("[]")
+        elem_java = (_generic_args(ret) or ["Object"])[0] if is_list else ret
+        elem_go = self.types.go_type(elem_java)
+        scan_struct = self.types.is_struct(elem_java)
+
+        lines = [
+            f"\trows, err := m.db.Query({query}, args...)",
+            "\tif err != nil {",
+            f"\t\treturn {zero_value(ret_go)}, err",
+            "\t}",
+            "\tdefer rows.Close()",
+        ]
+        if scan_struct:
+            columns = self.column_func(elem_java, statement.get("resultMap"))
+            new_elem = f"&{elem_go[1:]}{{}}"
+            scan = f"scanRow(rows, {columns}(%s))"
+        else:
+            new_elem = None
+            scan = "rows.Scan(nullable[" + elem_go + "]{&%s})"
+
+        if is_list:
+            lines.append(f"\tvar results {ret_go}")
+            lines.append("\tfor rows.Next() {")
+            if new_elem:
+                lines.append(f"\t\titem := {new_elem}")
+            else:
+                lines.append(f"\t\tvar item {elem_go}")
+            lines += [
+                f"\t\tif err := {scan % 'item'}; err != nil {{",
+                "\t\t\treturn nil, err",
+                "\t\t}",
+                "\t\tresults = append(results, item)",
+                "\t}",
+                "\treturn results, rows.Err()",
+            ]
+            return lines
+
+        lines += [
+            "\tif !rows.Next() {",
+            f"\t\treturn {zero_value(ret_go)}, rows.Err()",
+            "\t}",
+        ]
+        if new_elem:
+            lines.append(f"\tresult := {new_elem}")
+        else:
+            lines.append(f"\tvar result {elem_go}")
+        lines += [
+            f"\tif err := {scan % 'result'}; err != nil {{",
+            f"\t\treturn {zero_value(ret_go)}, err",
+            "\t}",
+            "\treturn result, nil",
+        ]
+        return lines
+
+    def column_func(self, java_type, result_map_id):
+        key = (_raw(java_type), result_map_id)
+        if key not in self.column_funcs:
+            suffix = exported(result_map_id) if result_map_id else _raw(java_type)
+            self.column_funcs[key] = f"{self.name[:1].lower()}{self.name[1:]}{suffix}Columns"
+        return self.column_funcs[key]
+
+    def generate_column_func(self, key, func_name):
+        java_type, result_map_id = key
+        struct = self.types.struct_name(java_type)
+        fields = self.type_fields.get(java_type, {})
+
+        columns = {}
+        for prop, field_type in fields.items():
+            columns[normalize_column(prop)] = (prop, field_type)
+        result_map = self.mapper["resultMaps"].get(result_map_id or "")
+        if result_map_id and result_map is None:
+            self.warnings.append(f"{self.name}: unknown resultMap {result_map_id}")
+        for column, prop in (result_map or {}).get("mappings", []):
+            if "." in prop:
+                self.warnings.append(f"{self.name}: nested property {prop} in resultMap {result_map_id} not mapped")
+                continue
+            columns[normalize_column(column)] = (prop, fields.get(prop, "Object"))
+
+        lines = [
+            f"// {func_name} maps normalised column names to the fields of a {struct}.",
+            f"func {func_name}(v *{struct}) map[string]any {{",
+            "\treturn map[string]any{",
+        ]
+        hidden = self.hidden_fields.get(java_type, ())
+        for column, (prop, field_type) in sorted(columns.items()):
+            if prop in hidden:
+                self.warnings.append(f"{self.name}: {struct}.{prop} is unexported (accessor logic); "
+                                     f"column {column} is not scanned — set it via Set{exported(prop)}")
+                continue
+            go_field_type = self.types.go_type(field_type)
+            if go_field_type.startswith("*") or go_field_type == "any":
+                self.warnings.append(f"{self.name}: {struct}.{exported(prop)} ({field_type}) cannot be scanned directly")
+                continue
+            lines.append(f'\t\t"{column}": nullable[{go_field_type}]{{&v.{exported(prop)}}},')
+        lines += ["\t}", "}"]
+        return lines
+
+
+def check_mapper_config(config):
+    """Generated mapper code scans into exported fields, which contradicts naming.fields: camelCase."""
+    if config and config["naming"]["fields"] != "exported":
+        raise ValueError("MyBatis mapper generation needs exported fields; "
+                         "it cannot be combined with naming.fields: camelCase")
+
+
+def _assign_go_names(params):
+    """Set p["goName"]: the Java name, suffixed with Param when it clashes with generated code."""
+    taken = {p["name"] for p in params}
+    for p in params:
+        name = p["name"]
+        if name in GENERATED_NAMES or name in GO_KEYWORDS or GENERATED_NAME_RE.fullmatch(name):
+            name = f"{name}Param"
+            while name in taken:
+                name += "_"
+            taken.add(name)
+        p["goName"] = name
+
+
+def normalize_column(name):
+    return name.lower().replace("_", "")
+
+
+WRITE_RE = re.compile(r'^(\t*)(\w+)\.WriteString\("((?:[^"\\]|\\.)*)"\)$')
+
+
+def _merge_writes(lines):
+    """Fold consecutive literal WriteString calls on the same builder into one."""
+    merged = []
+    for line in lines:
+        m = WRITE_RE.match(line)
+        prev = WRITE_RE.match(merged[-1]) if merged else None
+        if m and prev and m.group(1) == prev.group(1) and m.group(2) == prev.group(2):
+            text = re.sub(r" {2,}", " ", prev.group(3) + m.group(3))
+            merged[-1] = f'{m.group(1)}{m.group(2)}.WriteString("{text}")'
+        else:
+            merged.append(line)
+    return merged
+
+
+def _tabs(depth):
+    return "\t" * depth
+
+
+def _go_string(s):
+    return '"' + s.replace("\\", "\\\\").replace('"', '\\"') + '"'
+
+
+SUPPORT_FILE = """\
+// Code generated by Shadow-Code for converted MyBatis mappers. DO NOT EDIT.
+
+package {package}
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// DBTX is satisfied by *sql.DB and *sql.Tx, so mappers can run inside a transaction.
+type DBTX interface {{
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}}
+
+// SQLPlaceholder formats the n-th (1-based) bind parameter. The default suits
+// MySQL, SQLite and HSQLDB; set it to func(n int) string {{ return fmt.Sprintf("$%d", n) }} for Postgres.
+var SQLPlaceholder = func(n int) string {{ return "?" }}
+
+// bind writes a placeholder for v to q and records v as the next argument.
+func bind(q *strings.Builder, args *[]any, v any) {{
+	*args = append(*args, v)
+	q.WriteString(SQLPlaceholder(len(*args)))
+}}
+
+// trimSQL strips the first matching prefix and suffix override ("AND |OR ")
+// the way MyBatis <trim>, <where> and <set> do.
+func trimSQL(s, prefixOverrides, suffixOverrides string) string {{
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+	for _, p := range strings.Split(prefixOverrides, "|") {{
+		if p != "" && strings.HasPrefix(upper, strings.ToUpper(p)) {{
+			s = strings.TrimSpace(s[len(p):])
+			break
+		}}
+	}}
+	upper = strings.ToUpper(s)
+	for _, p := range strings.Split(suffixOverrides, "|") {{
+		if p != "" && strings.HasSuffix(upper, strings.ToUpper(p)) {{
+			s = strings.TrimSpace(s[:len(s)-len(p)])
+			break
+		}}
+	}}
+	return s
+}}
+
+// nullable scans a possibly-NULL column into *dst, leaving the zero value for NULL.
+type nullable[T any] struct{{ dst *T }}
+
+func (n nullable[T]) Scan(src any) error {{
+	var v sql.Null[T]
+	if err := v.Scan(src); err != nil {{
+		return err
+	}}
+	*n.dst = v.V
+	return nil
+}}
+
+// scanRow scans the current row into the destinations registered per column;
+// columns without a destination are discarded.
+func scanRow(rows *sql.Rows, fields map[string]any) error {{
+	cols, err := rows.Columns()
+	if err != nil {{
+		return err
+	}}
+	dests := make([]any, len(cols))
+	for i, c := range cols {{
+		if f, ok := fields[strings.ToLower(strings.ReplaceAll(c, "_", ""))]; ok {{
+			dests[i] = f
+		}} else {{
+			dests[i] = new(any)
+		}}
+	}}
+	return rows.Scan(dests...)
+}}
+"""
+
+
+def generate_support_file(go_package):
+    return SUPPORT_FILE.format(package=go_package)
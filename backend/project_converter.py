@@ -0,0 +1,813 @@
+# backend/project_converter.py
+"""
+Batch-convert a whole Java source tree into a multi-package Go module.
+
+The run is a pipeline: parse every file (in parallel), index types across the
+tree, transform files to Go on a bounded worker pool, and emit them in source
+order. A file that fails at any stage (parse, plan, convert, write, or the
+generated routes and mapper code) is reported as an error without stopping
+the others.
+
+Java packages are mapped to Go package directories (the common package
+prefix becomes the module root), cross-file type references are resolved
+to qualified Go imports, and a go.mod with the required modules is written
+alongside the output. MyBatis mapper XML found under the source tree (or
+--mappers) is turned into database/sql implementations of the mapper
+interfaces. The emitted module is then built and tested, with failures
+mapped back to their Java sources in the report.
+
+Usage:
+    python project_converter.py <java-src-dir> <output-dir> [--module NAME] [--config FILE] [--force]
+                                [--mappers DIR] [--workers N] [--idiomatic] [--no-verify]
+"""
+import argparse
+import json
+import os
+import re
+import sys
+
+from dotenv import load_dotenv
+
+from accessors import check_idiomatic_config, hidden_fields, parse_accessors
+from conversion_config import find_config, load_conversion_config
+from conversion_report import ConversionReport, analyze_constructs
+from conversion_cache import ConversionCache, cache_key
+from conversion_pipeline import default_workers, ordered_map
+from doc_comments import apply_doc_comments, extract_javadoc
+from gemini_client import CONVERTER_VERSION, convert_java_to_go, go_output_filename, is_junit_test
+from go_imports import fix_imports, write_go_mod
+from go_verify import verify_module
+from mybatis_mappers import (
+    GO_KEYWORDS, MapperGenerator, TypeMapper, check_mapper_config, exported, generate_support_file, parse_fields,
+    parse_mapper_interface, parse_mapper_xml,
+)
+from spring_controllers import generate_routes_file, parse_controller
+from type_hierarchy import parse_type_declarations
+from type_mappings import resolve_decimal_mapping, resolve_type_mappings
+
+PACKAGE_RE = re.compile(r'^\s*package\s+([\w.]+)\s*;', re.MULTILINE)
+IMPORT_RE = re.compile(r'^\s*import\s+(?:static\s+)?([\w.]+?)(\.\*)?\s*;', re.MULTILINE)
+TYPE_RE = re.compile(r'\b(?:class|interface|enum|record)\s+([A-Z]\w*)')
+TYPE_NAME_RE = re.compile(r'\b[A-Z]\w*')
+GO_PACKAGE_CLAUSE_RE = re.compile(r'^\s*package\s+\w+', re.MULTILINE)
+
+
+class ProjectConverter:
+    def __init__(self, source_root, output_root, module_name, analysis=None, use_gemini=True,
+                 decimal_type=None, config=None, force=False, mapper_roots=None, workers=None,
+                 idiomatic=False, verify=True):
+        self.source_root = os.path.abspath(source_root)
+        self.output_root = os.path.abspath(output_root)
+        self.module_name = module_name
+        self.analysis_by_path = {
+            os.path.abspath(v.get("filePath", "")): v for v in (analysis or {}).values()
+        }
+        self.use_gemini = use_gemini
+        self.decimal_type = decimal_type
+        self.config = config or load_conversion_config()
+        self.force = force
+        self.mapper_roots = [os.path.abspath(r) for r in (mapper_roots or [])]
+        self.workers = workers or default_workers()
+        self.idiomatic = idiomatic
+        self.verify = verify
+        self.verification = None
+        self.accessors = {} if idiomatic else None  # class name -> parse_accessors result
+        self.cache = None
+        self.report = None
+        self.files = []
+        self.errors = []      # (path, message) for files that failed to parse or convert
+        self.packages = {}    # java package -> {"dir", "importPath", "goPackage"}
+        self.type_index = {}  # simple type name -> java package
+        self.type_kinds = {}  # simple type name -> "interface" | "abstract" | "class"
+        self.type_fields = {}  # simple type name -> {field: java type}
+        self.mappers = []     # parsed MyBatis mapper XML
+        self.mapper_generators = {}  # mapper interface name -> MapperGenerator
+
+    def run(self):
+        os.makedirs(self.output_root, exist_ok=True)
+        self.cache = ConversionCache(self.output_root)
+        self.report = ConversionReport(self.source_root, self.output_root, CONVERTER_VERSION)
+
+        self.discover()
+        self.map_packages()
+        self.build_type_index()
+        self.check_package_cycles()
+        self.prepare_mappers()
+
+        written = self.convert_all()
+
+        written += self.write_routes()
+        written += self.write_mappers()
+
+        self.cache.save()
+        print(f"🗃️  Cache: {self.cache.hits} unchanged, {self.cache.misses} converted")
+
+        write_go_mod(self.output_root, self.module_name, written)
+        if self.verify:
+            self.verify_output()
+        self.report.write()
+        return written
+
+    def verify_output(self):
+        """Run go build / go test on the output and record per-package results in the report."""
+        print("🧪 Verifying converted module (go build, go test)...")
+        self.verification = verify_module(self.output_root)
+        self.report.add_verification(self.verification)
+
+        status = self.verification["status"]
+        if status in ("skipped", "error"):
+            print(f"⚠️  Verification {status}: {self.verification['reason']}")
+            return
+        for package in self.verification["packages"]:
+            ok = package["build"] == "passed" and package["tests"] != "failed"
+            build = f"blocked by {package['blockedBy']}" if package["blockedBy"] else package["build"]
+            print(f"{'✅' if ok else '❌'} {package['importPath']}: build {build}, tests {package['tests']}")
+            for error in package["errors"]:
+                origin = f" ← {error['javaFile']}" if error["javaFile"] else ""
+                print(f"     {error['goFile']}:{error['line']}: {error['message']}{origin}")
+            for test in package["failedTests"]:
+                origin = f" ← {test['javaFile']}" if test["javaFile"] else ""
+                print(f"     {test['name']} failed{origin}")
+        if status == "failed" and self.verification["reason"]:
+            print(f"❌ {self.verification['reason']}")
+
+    # ─── Discovery ─────────────────────────────────────────────
+
+    def discover(self):
+        paths = []
+        for root, dirs, names in os.walk(self.source_root):
+            dirs.sort()
+            paths += [os.path.join(root, name) for name in sorted(names) if name.endswith(".java")]
+
+        for path, file, error in ordered_map(self.parse_file, paths, self.workers):
+            if error:
+                self.fail(path, f"parse failed: {error}")
+            else:
+                self.files.append(file)
+
+        print(f"🔍 Found {len(self.files)} Java files under {self.source_root}")
+
+        for mapper_root in [self.source_root] + self.mapper_roots:
+            for root, dirs, names in os.walk(mapper_root):
+                dirs.sort()
+                for name in sorted(names):
+                    if not name.endswith(".xml"):
+                        continue
+                    path = os.path.join(root, name)
+                    try:
+                        mapper = parse_mapper_xml(path)
+                    except (OSError, UnicodeDecodeError) as e:
+                        self.fail(path, f"mapper XML unreadable: {e}")
+                        continue
+                    if mapper:
+                        self.mappers.append(mapper)
+        if self.mappers:
+            print(f"🗄️  Found {len(self.mappers)} MyBatis mapper XML files")
+            check_mapper_config(self.config)
+
+    def parse_file(self, path):
+        with open(path, "r", encoding="utf-8", errors="replace") as f:
+            source = f.read()
+
+        pkg_match = PACKAGE_RE.search(source)
+        return {
+            "filePath": path,
+            "source": source,
+            "javaPackage": pkg_match.group(1) if pkg_match else "",
+            "imports": [(m.group(1), bool(m.group(2))) for m in IMPORT_RE.finditer(source)],
+            "types": TYPE_RE.findall(source),
+            "declarations": parse_type_declarations(source),
+        }
+
+    def fail(self, path, message, findings=None):
+        """Record a per-file failure; the rest of the run carries on."""
+        print(f"❌ {os.path.relpath(path, self.source_root)}: {message}")
+        self.errors.append((path, message))
+        self.report.add_file(path, None, findings or [], "error", error=message)
+
+    def map_packages(self):
+        """Map each Java package to a Go package directory below the common prefix."""
+        java_packages = sorted({f["javaPackage"] for f in self.files})
+        prefix = _common_package_prefix([p for p in java_packages if p])
+
+        renames = self.config["packageRenames"]
+
+        for pkg in java_packages:
+            parts = pkg.split(".") if pkg else []
+            if pkg in renames:
+                rel_dir = renames[pkg]
+                go_package = _go_package_name(rel_dir.split("/")[-1]) if rel_dir else "main"
+            else:
+                rel_dir = "/".join(_go_package_name(p) for p in parts[len(prefix):])
+                go_package = _go_package_name(parts[-1]) if parts else "main"
+            import_path = f"{self.module_name}/{rel_dir}" if rel_dir else self.module_name
+
+            self.packages[pkg] = {
+                "dir": rel_dir,
+                "importPath": import_path,
+                "goPackage": go_package,
+            }
+
+    def build_type_index(self):
+        for file in self.files:
+            for type_name in file["types"]:
+                self.type_index.setdefault(type_name, file["javaPackage"])
+            for decl in file["declarations"]:
+                self.type_kinds.setdefault(decl["name"], decl["kind"])
+            for type_name in file["types"][:1]:
+                self.type_fields.setdefault(type_name, parse_fields(file["source"]))
+            if self.idiomatic:
+                accessors = parse_accessors(file["source"])
+                if accessors:
+                    self.accessors.setdefault(accessors["className"], accessors)
+
+    def check_package_cycles(self):
+        """
+        Warn about Go packages that would import each other. Each Java package
+        becomes one Go package, and Java allows mutual imports that Go rejects.
+        """
+        graph = {}  # import path -> {imported import path: {java files}}
+        for file in self.files:
+            own = self.packages[file["javaPackage"]]["importPath"]
+            for pkg in self.resolve_references(file).values():
+                graph.setdefault(own, {}).setdefault(self.packages[pkg]["importPath"], set()).add(
+                    os.path.relpath(file["filePath"], self.source_root)
+                )
+
+        for cycle in _import_cycles({src: set(dsts) for src, dsts in graph.items()}):
+            edges = [
+                {"from": src, "to": dst, "javaFiles": sorted(graph[src][dst])}
+                for src in cycle for dst in cycle if dst in graph.get(src, {})
+            ]
+            print(
+                f"⚠️  Import cycle between {', '.join(cycle)}: Go rejects it; "
+                "map these Java packages to one directory with packageRenames"
+            )
+            for edge in edges:
+                print(f"     {edge['from']} → {edge['to']} ({', '.join(edge['javaFiles'])})")
+            self.report.add_package_cycle(cycle, edges)
+
+    def hidden_fields(self):
+        """Class name -> fields left unexported by idiomatic mode because their accessors have logic."""
+        return {name: hidden_fields(acc) for name, acc in (self.accessors or {}).items()}
+
+    def prepare_mappers(self):
+        """Pair each mapper XML with its Java interface so both sides agree on the Go method set."""
+        by_qualified_name = {
+            f"{f['javaPackage']}.{t}" if f["javaPackage"] else t: (f, t) for f in self.files for t in f["types"]
+        }
+        for mapper in self.mappers:
+            match = by_qualified_name.get(mapper["namespace"])
+            if not match:
+                print(f"⚠️  No Java interface for mapper namespace {mapper['namespace']} ({mapper['path']})")
+                continue
+            file, name = match
+            pkg = file["javaPackage"]
+            types = self.type_mapper(pkg)
+            try:
+                self.mapper_generators[name] = MapperGenerator(
+                    mapper,
+                    parse_mapper_interface(file["source"]),
+                    self.packages[pkg]["goPackage"],
+                    types,
+                    self.type_fields,
+                    name,
+                    hidden_fields=self.hidden_fields(),
+                )
+            except Exception as e:
+                self.fail(mapper["path"], f"mapper preparation failed: {e}")
+                continue
+            mapper["file"] = file
+
+    # ─── Cross-file references ─────────────────────────────────
+
+    def resolve_references(self, file):
+        """
+        Return the project types this file refers to that live in other Go
+        packages, as {type name: java package}.
+        """
+        refs = {}
+        # Java packages renamed into the same directory share a Go package.
+        own = self.packages[file["javaPackage"]]["importPath"]
+
+        def other_package(pkg):
+            return pkg in self.packages and self.packages[pkg]["importPath"] != own
+
+        for imp, wildcard in file["imports"]:
+            if wildcard:
+                if other_package(imp):
+                    for type_name, pkg in self.type_index.items():
+                        if pkg == imp and re.search(rf"\b{type_name}\b", file["source"]):
+                            refs[type_name] = pkg
+                continue
+
+            pkg, _, type_name = imp.rpartition(".")
+            if other_package(pkg) and self.type_index.get(type_name) == pkg:
+                refs[type_name] = pkg
+
+        return refs
+
+    def referenced_types(self, file):
+        """Project types named anywhere in the file: its imports, supertypes and resolved references."""
+        return sorted(set(TYPE_NAME_RE.findall(file["source"])) & set(self.type_index))
+
+    def package_context(self, file, list_same_package=True):
+        """Prompt context for a file; the cache key omits the same-package type list."""
+        target = self.packages[file["javaPackage"]]
+        same_package = list_same_package and sorted(
+            t for t, pkg in self.type_index.items()
+            if self.packages[pkg]["importPath"] == target["importPath"] and t not in file["types"]
+        )
+        refs = self.resolve_references(file)
+
+        lines = [
+            f"Module: {self.module_name}",
+            f"Target package: package {target['goPackage']} (import path {target['importPath']})",
+            "This file is one of a multi-package Go module; emit exactly that package clause.",
+        ]
+        if same_package:
+            lines.append(f"Types in the same package (reference unqualified): {', '.join(same_package)}")
+        if refs:
+            lines.append("Types from other packages (import and qualify them):")
+            for type_name, pkg in sorted(refs.items()):
+                other = self.packages[pkg]
+                lines.append(f'  - {type_name} → {other["goPackage"]}.{type_name} (import "{other["importPath"]}")')
+
+        controller = parse_controller(file["source"])
+        if controller:
+            params = ", ".join(
+                f"{n} {self.go_type_for(t, file['javaPackage'])}" for t, n in controller["dependencies"]
+            )
+            lines.append(
+                f"routes.go calls the constructor as exactly: func New{controller['name']}({params}) *{controller['name']}"
+            )
+
+        for type_name in file["types"]:
+            generator = self.mapper_generators.get(type_name)
+            if generator:
+                lines.append(
+                    f"{type_name} is a MyBatis mapper; a generated SQL{type_name} implements it, "
+                    "so declare the Go interface with exactly these methods:"
+                )
+                lines += [f"  {sig}" for sig in generator.interface_methods()]
+
+        return "\n".join(lines)
+
+    # ─── Emission ──────────────────────────────────────────────
+
+    def convert_all(self):
+        """Transform files on the worker pool and emit them in source order."""
+        jobs = []
+        for file in self.files:
+            try:
+                jobs.append(self.plan_file(file))
+            except Exception as e:
+                self.fail(file["filePath"], f"planning failed: {e}")
+
+        written = []
+        for job, go_code, error in ordered_map(self.transform, jobs, self.workers):
+            if error:
+                self.fail(job["file"]["filePath"], f"conversion failed: {error}", job["findings"])
+                continue
+            try:
+                written.append(self.emit(job, go_code))
+            except Exception as e:
+                self.fail(job["file"]["filePath"], f"write failed: {e}", job["findings"])
+        return written
+
+    def plan_file(self, file):
+        """Work out where a file goes and whether the cache already has it (main thread only)."""
+        target = self.packages[file["javaPackage"]]
+        out_dir = os.path.join(self.output_root, *target["dir"].split("/")) if target["dir"] else self.output_root
+        out_name = go_output_filename(os.path.basename(file["filePath"]), file["source"])
+        out_path = os.path.join(out_dir, out_name)
+
+        rel_path = os.path.relpath(file["filePath"], self.source_root)
+        package_context = self.package_context(file)
+        # Key only on the types this file references, so editing an unrelated file leaves it cached.
+        referenced = self.referenced_types(file)
+        key = cache_key(
+            file["source"],
+            CONVERTER_VERSION,
+            gemini=self.use_gemini,
+            output=os.path.relpath(out_path, self.output_root),
+            packageContext=self.package_context(file, list_same_package=False),
+            decimalType=self.decimal_type,
+            knownKinds={t: self.type_kinds[t] for t in referenced if t in self.type_kinds},
+            config=self.config,
+            accessors=None if self.accessors is None else {
+                t: self.accessors[t] for t in referenced if t in self.accessors
+            },
+        )
+        # --force skips the lookup, but a file that then fails must not lose its entry.
+        self.cache.mark_seen(rel_path)
+        cached = not self.force and self.cache.lookup(rel_path, key) == out_path
+        return {
+            "file": file,
+            "target": target,
+            "outPath": out_path,
+            "relPath": rel_path,
+            "packageContext": package_context,
+            "key": key,
+            "cached": cached,
+            "findings": analyze_constructs(file["source"]),
+        }
+
+    def transform(self, job):
+        """Produce the Go source for a job, or None when cached. Runs on worker threads."""
+        if job["cached"]:
+            return None
+        file, target = job["file"], job["target"]
+
+        if self.use_gemini:
+            node_analysis = self.analysis_by_path.get(os.path.abspath(file["filePath"]), {})
+            go_code = convert_java_to_go(
+                file["source"], node_analysis, file["filePath"], job["packageContext"],
+                decimal_type=self.decimal_type,
+                known_kinds=self.type_kinds,
+                config=self.config,
+                accessors=self.accessors,
+            )
+        else:
+            print(f"💳 [GEMINI DISABLED] Generating placeholder Go for {file['filePath']}")
+            field_types, imports = self.placeholder_fields(file)
+            for type_name in file["types"]:
+                if type_name in self.mapper_generators:
+                    imports |= set(self.mapper_generators[type_name].types.imports)
+            go_code = _placeholder_go(file, target["goPackage"],
+                                      qualify=lambda t: self.go_type_for(t, file["javaPackage"]),
+                                      interface_methods={
+                                          name: g.interface_methods() for name, g in self.mapper_generators.items()
+                                      },
+                                      field_types=field_types,
+                                      imports=imports,
+                                      accessors=(self.accessors or {}).get(file["types"][0]) if file["types"] else None)
+
+        go_code = _force_package_clause(go_code, target["goPackage"])
+        go_code = apply_doc_comments(go_code, file["source"])
+        return fix_imports(go_code, self.local_packages(file), self.module_name)
+
+    def emit(self, job, go_code):
+        file, out_path = job["file"], job["outPath"]
+        if job["cached"]:
+            print(f"⏭️  {job['relPath']} unchanged, skipping")
+            self.report.add_file(file["filePath"], out_path, job["findings"], "cached")
+            return out_path
+
+        os.makedirs(os.path.dirname(out_path), exist_ok=True)
+        with open(out_path, "w") as f:
+            f.write(go_code + ("" if go_code.endswith("\n") else "\n"))
+
+        self.cache.store(job["relPath"], job["key"], out_path)
+        self.report.add_file(file["filePath"], out_path, job["findings"], "converted")
+        print(f"💾 {job['relPath']} → {os.path.relpath(out_path, self.output_root)}")
+        return out_path
+
+    def go_type_for(self, java_type, from_package):
+        """Go type expression for a project type referenced from from_package (interfaces by value)."""
+        pkg = self.type_index.get(java_type)
+        if pkg is None:
+            return f"*{java_type}"
+        same = self.packages[pkg]["importPath"] == self.packages[from_package]["importPath"]
+        qualifier = "" if same else f"{self.packages[pkg]['goPackage']}."
+        pointer = "" if self.type_kinds.get(java_type) in ("interface", "abstract") else "*"
+        return f"{pointer}{qualifier}{java_type}"
+
+    def write_routes(self):
+        """Write routes.go into every Go package that holds converted Spring controllers."""
+        by_package = {}
+        for file in self.files:
+            controller = parse_controller(file["source"])
+            if controller:
+                # Keyed by directory: Java packages renamed into one directory share its routes.go.
+                by_package.setdefault(self.packages[file["javaPackage"]]["dir"], []).append((file, controller))
+
+        written = []
+        for _, entries in sorted(by_package.items()):
+            java_pkg = entries[0][0]["javaPackage"]
+            target = self.packages[java_pkg]
+            out_dir = os.path.join(self.output_root, *target["dir"].split("/")) if target["dir"] else self.output_root
+            out_path = os.path.join(out_dir, "routes.go")
+            try:
+                code = generate_routes_file(
+                    target["goPackage"],
+                    [c for _, c in entries],
+                    qualify=lambda t, pkg=java_pkg: self.go_type_for(t, pkg),
+                )
+                code = fix_imports(code, self.local_packages(entries[0][0]), self.module_name)
+                os.makedirs(out_dir, exist_ok=True)
+                with open(out_path, "w") as f:
+                    f.write(code)
+            except Exception as e:
+                # Reported against the controllers' source directory, i.e. the Java package.
+                self.fail(os.path.dirname(entries[0][0]["filePath"]), f"routes.go generation failed: {e}")
+                continue
+            routes = sum(len(c["routes"]) for _, c in entries)
+            print(f"🌐 Wrote {os.path.relpath(out_path, self.output_root)} ({len(entries)} controllers, {routes} routes)")
+            written.append(out_path)
+        return written
+
+    def type_mapper(self, java_pkg):
+        """TypeMapper resolving types from java_pkg with the same mappings the prompt uses."""
+        return TypeMapper(
+            lambda t: self.go_type_for(t, java_pkg) if t in self.type_index else None,
+            resolve_type_mappings(self.decimal_type, self.config["typeMappings"]),
+        )
+
+    def placeholder_fields(self, file):
+        """
+        ({field: Go type}, imports) for the file's primary class, so placeholder
+        structs still match mapper code.
+        """
+        if not file["types"]:
+            return {}, set()
+        types = self.type_mapper(file["javaPackage"])
+        fields = {name: types.go_type(t) for name, t in self.type_fields.get(file["types"][0], {}).items()}
+        return fields, types.imports
+
+    def write_mappers(self):
+        """Write <mapper>_sql.go for every paired mapper, plus mybatis_support.go once per package."""
+        written, support_packages = [], {}
+        for name, generator in sorted(self.mapper_generators.items()):
+            file = generator.mapper["file"]
+            target = self.packages[file["javaPackage"]]
+            out_dir = os.path.join(self.output_root, *target["dir"].split("/")) if target["dir"] else self.output_root
+            out_path = os.path.join(out_dir, f"{name.lower()}_sql.go")
+            try:
+                code = fix_imports(generator.generate(), self.local_packages(file), self.module_name)
+                os.makedirs(out_dir, exist_ok=True)
+                with open(out_path, "w") as f:
+                    f.write(code)
+            except Exception as e:
+                self.fail(generator.mapper["path"], f"mapper generation failed: {e}")
+                continue
+            support_packages[out_dir] = (target["goPackage"], generator.mapper["path"])
+
+            findings = [
+                {"rule": "mybatis", "category": "approximated", "line": 0, "snippet": "", "message": w}
+                for w in generator.warnings
+            ]
+            self.report.add_file(generator.mapper["path"], out_path, findings, "generated")
+            print(f"🗄️  {os.path.relpath(generator.mapper['path'], self.source_root)} → "
+                  f"{os.path.relpath(out_path, self.output_root)} ({len(generator.methods)} statements)")
+            written.append(out_path)
+
+        for out_dir, (go_package, mapper_path) in sorted(support_packages.items()):
+            out_path = os.path.join(out_dir, "mybatis_support.go")
+            try:
+                with open(out_path, "w") as f:
+                    f.write(generate_support_file(go_package))
+            except OSError as e:
+                self.fail(mapper_path, f"writing mybatis_support.go failed: {e}")
+                continue
+            written.append(out_path)
+        return written
+
+    def local_packages(self, file):
+        """Package name → import path for the module's other packages, used to fill in missing imports."""
+        own = self.packages[file["javaPackage"]]["importPath"]
+        return {
+            p["goPackage"]: p["importPath"] for p in self.packages.values() if p["importPath"] != own
+        }
+
+
+def _import_cycles(graph):
+    """Strongly connected groups of two or more nodes in {node: {neighbours}}, each sorted (Tarjan)."""
+    index, low, stack, on_stack, groups = {}, {}, [], set(), []
+
+    def visit(node):
+        index[node] = low[node] = len(index)
+        stack.append(node)
+        on_stack.add(node)
+        for nei in sorted(graph.get(node, ())):
+            if nei not in index:
+                visit(nei)
+                low[node] = min(low[node], low[nei])
+            elif nei in on_stack:
+                low[node] = min(low[node], index[nei])
+        if low[node] == index[node]:
+            group = []
+            while True:
+                member = stack.pop()
+                on_stack.discard(member)
+                group.append(member)
+                if member == node:
+                    break
+            if len(group) > 1:
+                groups.append(sorted(group))
+
+    for node in sorted(graph):
+        if node not in index:
+            visit(node)
+    return sorted(groups)
+
+
+def _common_package_prefix(packages):
+    if not packages:
+        return []
+    split = [p.split(".") for p in packages]
+    prefix = []
+    for parts in zip(*split):
+        if len(set(parts)) != 1:
+            break
+        prefix.append(parts[0])
+    # Keep at least the last segment so a single-package project still gets a package name.
+    if len(packages) == 1 or all(len(s) == len(prefix) for s in split):
+        prefix = prefix[:-1]
+    return prefix
+
+
+def _go_package_name(segment):
+    name = re.sub(r"[^a-z0-9_]", "", segment.lower())
+    if not name or name in GO_KEYWORDS:
+        name = f"{name}pkg"
+    return name
+
+
+def _force_package_clause(go_code, go_package):
+    """Make sure the emitted file declares the package it was placed in."""
+    if GO_PACKAGE_CLAUSE_RE.search(go_code):
+        return GO_PACKAGE_CLAUSE_RE.sub(f"package {go_package}", go_code, count=1)
+    return f"package {go_package}\n\n{go_code}"
+
+
+def _placeholder_go(file, go_package, qualify=None, interface_methods=None, field_types=None, accessors=None,
+                    imports=None):
+    """
+    Return a placeholder Go file when Gemini API is disabled. accessors is the
+    idiomatic-mode parse_accessors result for the primary class, if any.
+    """
+    types = file["types"] or [os.path.basename(file["filePath"]).replace(".java", "")]
+    # Types with Javadoc get it back from apply_doc_comments instead of the generic line.
+    documented = {d["name"] for d in extract_javadoc(file["source"]) if d["kind"] == "type"}
+
+    def doc(type_name, what):
+        return "" if type_name in documented else f"// {type_name} is the Go equivalent of the Java {what}.\n"
+
+    parts = [
+        f"package {go_package}\n\n",
+        "".join(f'import "{path}"\n' for path in sorted(imports or ())) + ("\n" if imports else ""),
+        f"// AUTO-GENERATED PLACEHOLDER (credit-safe mode)\n",
+        f"// Original: {file['filePath']}\n",
+    ]
+    if is_junit_test(file["source"]):
+        parts.insert(1, 'import "testing"\n\n')
+        for type_name in types:
+            parts.append(
+                f"\nfunc Test{type_name}(t *testing.T) {{\n"
+                f"\tt.Skip(\"TODO: translate JUnit tests from Java source\")\n"
+                f"}}\n"
+            )
+        return "".join(parts)
+    controller = parse_controller(file["source"])
+    if controller:
+        name = controller["name"]
+        params = ", ".join(f"{n} {qualify(t) if qualify else '*' + t}" for t, n in controller["dependencies"])
+        parts.append(
+            f"\n{doc(name, 'controller')}"
+            f"type {name} struct {{\n"
+            f"\t// TODO: translate fields from Java source\n"
+            f"}}\n\n"
+            f"func New{name}({params}) *{name} {{\n"
+            f"\treturn &{name}{{}}\n"
+            f"}}\n"
+        )
+        for handler in sorted({r["handler"] for r in controller["routes"]}):
+            parts.append(
+                f"\nfunc (c *{name}) {handler}(w http.ResponseWriter, r *http.Request) {{\n"
+                f"\thttp.Error(w, \"not implemented\", http.StatusNotImplemented)\n"
+                f"}}\n"
+            )
+        return "".join(parts)
+
+    kinds = {d["name"]: d["kind"] for d in file["declarations"]}
+    for type_name in types:
+        if kinds.get(type_name) == "interface":
+            methods = (interface_methods or {}).get(type_name)
+            body = "".join(f"\t{m}\n" for m in methods) if methods else "\t// TODO: translate methods from Java source\n"
+            parts.append(
+                f"\n{doc(type_name, 'interface')}"
+                f"type {type_name} interface {{\n"
+                f"{body}"
+                f"}}\n"
+            )
+            continue
+        primary = type_name == types[0]
+        hidden = hidden_fields(accessors) if accessors and primary else set()
+        fields = "".join(
+            f"\t{n if n in hidden else exported(n)} {t}\n" for n, t in (field_types or {}).items()
+        )
+        if not primary or not fields:
+            fields = "\t// TODO: translate fields from Java source\n"
+        parts.append(
+            f"\n{doc(type_name, 'type')}"
+            f"type {type_name} struct {{\n"
+            f"{fields}"
+            f"}}\n"
+        )
+        # Hidden fields are only reachable through X() / SetX(v), which other files (and mappers) call.
+        recv = type_name[:1].lower()
+        for prop in sorted(hidden & set(field_types or {})):
+            p, go_type = accessors["properties"][prop], field_types[prop]
+            if p["getter"]:
+                parts.append(
+                    f"\n// {exported(prop)} returns the {prop} field.\n"
+                    f"func ({recv} *{type_name}) {exported(prop)}() {go_type} {{\n"
+                    f"\t// TODO: translate the logic of {p['getter']} from Java source\n"
+                    f"\treturn {recv}.{prop}\n"
+                    f"}}\n"
+                )
+            if p["setter"]:
+                parts.append(
+                    f"\n// Set{exported(prop)} sets the {prop} field.\n"
+                    f"func ({recv} *{type_name}) Set{exported(prop)}(v {go_type}) {{\n"
+                    f"\t// TODO: translate the logic of {p['setter']} from Java source\n"
+                    f"\t{recv}.{prop} = v\n"
+                    f"}}\n"
+                )
+    return "".join(parts)
+
+
+def _load_analysis(base_dir):
+    analysis_path = os.path.join(base_dir, "storage", "analysis.json")
+    if not os.path.exists(analysis_path):
+        return {}
+    with open(analysis_path, "r") as f:
+        return json.load(f)
+
+
+if __name__ == "__main__":
+    load_dotenv()
+
+    parser = argparse.ArgumentParser(description="Convert a Java source tree into a multi-package Go module.")
+    parser.add_argument("source", help="Java source root (e.g. repos/jpetstore-6/src/main/java)")
+    parser.add_argument("output", help="Directory to write the Go module into")
+    parser.add_argument("--module", help="Go module path (defaults to the output directory name)")
+    parser.add_argument("--decimal-type", default=None,
+                        help='Go type for BigDecimal: "shopspring" (default), "bigfloat", or "import/path.Type"')
+    parser.add_argument("--config", default=None,
+                        help="Conversion config (defaults to shadowcode.yaml/.yml/.json in the source dir or cwd)")
+    parser.add_argument("--force", action="store_true",
+                        help="Reconvert every file, ignoring the content-hash cache")
+    parser.add_argument("--idiomatic", action="store_true",
+                        help="Collapse trivial getters/setters into exported fields and rewrite their call sites")
+    parser.add_argument("--no-verify", action="store_true",
+                        help="Skip running go build / go test on the converted module")
+    parser.add_argument("--workers", type=int, default=None,
+                        help="Files converted in parallel (default: SHADOWCODE_WORKERS or 4)")
+    parser.add_argument("--mappers", action="append", default=None,
+                        help="Extra directory to search for MyBatis mapper XML (defaults to a sibling resources/ dir)")
+    args = parser.parse_args()
+
+    if not os.path.isdir(args.source):
+        print(f"❌ Source directory not found: {args.source}")
+        sys.exit(1)
+
+    use_gemini = not os.getenv("DISABLE_GEMINI_API", "true").lower() in ("true", "1", "yes")
+    module_name = args.module or os.path.basename(os.path.abspath(args.output))
+
+    config_path = args.config or find_config(args.source) or find_config(os.getcwd())
+    try:
+        resolve_decimal_mapping(args.decimal_type)
+        config = load_conversion_config(config_path)
+        if args.idiomatic:
+            check_idiomatic_config(config)
+    except ValueError as e:
+        print(f"❌ {e}")
+        sys.exit(1)
+    if config_path:
+        print(f"⚙️  Using conversion config {config_path}")
+
+    mapper_roots = args.mappers
+    if mapper_roots is None:
+        resources = os.path.join(os.path.dirname(os.path.abspath(args.source)), "resources")
+        mapper_roots = [resources] if os.path.isdir(resources) else []
+
+    converter = ProjectConverter(
+        args.source,
+        args.output,
+        module_name,
+        analysis=_load_analysis(os.path.dirname(os.path.abspath(__file__))),
+        use_gemini=use_gemini,
+        decimal_type=args.decimal_type,
+        config=config,
+        force=args.force,
+        mapper_roots=mapper_roots,
+        workers=args.workers,
+        idiomatic=args.idiomatic,
+        verify=not args.no_verify,
+    )
+    try:
+        written = converter.run()
+    except ValueError as e:
+        print(f"❌ {e}")
+        sys.exit(1)
+    if converter.errors:
+        print(f"⚠️  Converted {len(written)} files into module {module_name}; "
+              f"{len(converter.errors)} failed (see conversion-report.md)")
+        sys.exit(1)
+    if converter.verification and converter.verification["status"] == "failed":
+        print(f"⚠️  Converted {len(written)} files into module {module_name}, "
+              "but it does not build or its tests fail (see conversion-report.md)")
+        sys.exit(1)
+    print(f"[OK] Converted {len(written)} files into module {module_name} at {converter.output_root}")
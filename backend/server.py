@@ -14,7 +14,18 @@ from backboard_client import (
     send_to_backboard,
     extract_verdict,
 )
-from gemini_client import convert_java_to_go, convert_java_to_kotlin, convert_java_to_typescript
+from gemini_client import (
+    convert_java_to_go,
+    convert_java_to_kotlin,
+    convert_java_to_typescript,
+    go_output_filename,
+)
+from type_mappings import resolve_decimal_mapping
+from conversion_config import find_config, load_conversion_config
+from go_imports import fix_imports
+from conversion_report import analyze_constructs
+from accessors import check_idiomatic_config, parse_accessors
+from doc_comments import apply_doc_comments
 from datetime import datetime, timezone
 
 # Load .env file if present
@@ -265,6 +276,25 @@ def _record_conversion(node_id, original_file, converted_filename, target_langua
     print(f'📝 Recorded conversion: {original_file} → {converted_filename} ({target_language})')
 
 
+def _load_conversion_config():
+    """Load the conversion config from SHADOWCODE_CONFIG or a shadowcode.* file in the backend dir."""
+    return load_conversion_config(os.getenv('SHADOWCODE_CONFIG') or find_config(BASE_DIR))
+
+
+def _accessor_index(analysis):
+    """Accessor info for every analysed Java class, so idiomatic mode rewrites call sites consistently."""
+    index = {}
+    for node in analysis.values():
+        path = node.get('filePath', '')
+        if not path.endswith('.java') or not os.path.exists(path):
+            continue
+        with open(path, 'r', encoding='utf-8', errors='replace') as f:
+            accessors = parse_accessors(f.read())
+        if accessors:
+            index.setdefault(accessors['className'], accessors)
+    return index
+
+
 def _get_locked_language():
     """Return the locked target language for the current project, or None."""
     project_id = _get_project_id_from_analysis()
@@ -681,6 +711,7 @@ def convert_code():
     """
     Convert a Java file to Go, Kotlin, or TypeScript using Gemini.
     Expects JSON body: { "nodeId": "file_0", "targetLanguage": "go" | "kotlin" | "typescript" }
+    Optional for Go: { "decimalType": "shopspring" | "bigfloat" | "import/path.Type", "idiomatic": true }
     """
     data = request.get_json()
     if not data or 'nodeId' not in data:
@@ -692,6 +723,14 @@ def convert_code():
     if target_language not in ('go', 'kotlin', 'typescript'):
         return jsonify({'error': f'Unsupported target language: {target_language}. Use "go", "kotlin", or "typescript".'}), 400
 
+    decimal_type = data.get('decimalType')
+    try:
+        resolve_decimal_mapping(decimal_type)
+        if data.get('idiomatic'):
+            check_idiomatic_config(_load_conversion_config())
+    except ValueError as e:
+        return jsonify({'error': str(e)}), 400
+
     # Language lock enforcement
     # locked = _get_locked_language()
     # if locked and locked != target_language:
@@ -731,7 +770,7 @@ def convert_code():
             out_filename = original_name.replace('.java', '.ts')
             lang_label = 'TypeScript'
         else:
-            out_filename = original_name.replace('.java', '.go').lower()
+            out_filename = go_output_filename(original_name, source_code)
             lang_label = 'Go'
 
         # Convert (or placeholder if Gemini API disabled)
@@ -750,7 +789,12 @@ def convert_code():
             elif target_language == 'typescript':
                 converted_code = convert_java_to_typescript(source_code, node_analysis, file_path)
             else:
-                converted_code = convert_java_to_go(source_code, node_analysis, file_path)
+                converted_code = fix_imports(apply_doc_comments(convert_java_to_go(
+                    source_code, node_analysis, file_path,
+                    decimal_type=decimal_type,
+                    config=_load_conversion_config(),
+                    accessors=_accessor_index(analysis) if data.get('idiomatic') else None,
+                ), source_code))
 
         # Record the conversion
         # Use provided project_id or derive it
@@ -769,6 +813,8 @@ def convert_code():
             'goCode': converted_code,
             'javaSource': source_code,
             'targetLanguage': lang_label,
+            # The findings describe Go translations (error returns, sync.Mutex, ...).
+            'warnings': analyze_constructs(source_code) if target_language == 'go' else [],
         })
 
     except ValueError as e:
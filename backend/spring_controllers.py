@@ -0,0 +1,313 @@
+# backend/spring_controllers.py
+"""
+Spring MVC controller support: detects @Controller / @RestController classes,
+tells Gemini how to shape them as net/http handlers, and generates the router
+registration file that wires every handler onto an http.ServeMux.
+
+Routes use Go 1.22 ServeMux patterns ("GET /catalog/{id}"), so Spring path
+variables carry over unchanged and handlers read them with r.PathValue.
+"""
+import re
+
+from mybatis_mappers import exported
+
+CONTROLLER_RE = re.compile(r"@(?:Rest)?Controller\b")
+REST_CONTROLLER_RE = re.compile(r"@RestController\b|@ResponseBody\b")
+CLASS_RE = re.compile(r"\bclass\s+([A-Z]\w*)")
+MAPPING_RE = re.compile(
+    r"@(?P<kind>Request|Get|Post|Put|Delete|Patch)Mapping\b\s*(?:\((?P<args>[^)]*)\))?"
+)
+METHOD_AFTER_RE = re.compile(r"\s*(?:@\w+(?:\([^)]*\))?\s*)*(?:(?:public|protected|private|static|final)\s+)*"
+                             r"[\w<>\[\],.?\s]+?\s+(\w+)\s*\((?P<params>[^)]*)\)")
+PATH_VARIABLE_RE = re.compile(r"@PathVariable\b(?:\((?P<args>[^)]*)\))?\s+(?:final\s+)?[\w<>\[\].]+\s+(?P<name>\w+)")
+REQUEST_PARAM_RE = re.compile(r"@RequestParam\b(?:\((?P<args>[^)]*)\))?\s+(?:final\s+)?[\w<>\[\].]+\s+(?P<name>\w+)")
+REQUEST_BODY_RE = re.compile(r"@RequestBody\b\s+(?:final\s+)?(?P<type>[\w<>\[\].]+)\s+(?P<name>\w+)")
+AUTOWIRED_FIELD_RE = re.compile(
+    r"@(?:Autowired|Inject|Resource)\b[^;{]*?\s(?:private|protected|public)?\s*(?:final\s+)?"
+    r"(?P<type>[A-Z][\w<>]*)\s+(?P<name>\w+)\s*;"
+)
+FINAL_FIELD_RE = re.compile(r"\bprivate\s+final\s+(?P<type>[A-Z][\w<>]*)\s+(?P<name>\w+)\s*;")
+STRING_LITERAL_RE = re.compile(r'"([^"]*)"')
+WILDCARD_SEGMENT_RE = re.compile(r"\{(\w+)(?:\.\.\.)?\}")
+NAMED_ARG_RE = re.compile(r"\b(?P<key>value|path|name|method)\s*=\s*(?P<val>\{[^}]*\}|[^,]+)")
+
+HTTP_METHODS = {"Get": "GET", "Post": "POST", "Put": "PUT", "Delete": "DELETE", "Patch": "PATCH"}
+
+
+def is_controller(source_code):
+    return bool(CONTROLLER_RE.search(source_code))
+
+
+def _mapping_paths(args):
+    """Path strings from mapping annotation args: ("/a"), (value = {"/a", "/b"}), (path = "/a", method = ...)."""
+    if not args:
+        return [""]
+    named = {m.group("key"): m.group("val") for m in NAMED_ARG_RE.finditer(args)}
+    source = named.get("value") or named.get("path")
+    if source is None and not named:
+        source = args
+    paths = STRING_LITERAL_RE.findall(source or "")
+    return paths or [""]
+
+
+def _mapping_methods(args):
+    named = {m.group("key"): m.group("val") for m in NAMED_ARG_RE.finditer(args or "")}
+    return re.findall(r"RequestMethod\.(\w+)", named.get("method", ""))
+
+
+def _arg_name(args, default):
+    if not args:
+        return default
+    named = {m.group("key"): m.group("val") for m in NAMED_ARG_RE.finditer(args)}
+    literal = STRING_LITERAL_RE.findall(named.get("value") or named.get("name") or args)
+    return literal[0] if literal else default
+
+
+def to_go_pattern(path):
+    """
+    Spring path → ServeMux pattern: {id:\\d+} → {id}, * → {wildcard1}, a trailing
+    /** → {rest...}. Returns None when ServeMux cannot express the path: wildcards
+    inside a segment (/items/{id}.json), a non-trailing ** or a repeated name.
+    """
+    if path == "/":
+        # A bare "/" is a catch-all in ServeMux; Spring's root mapping matches only "/".
+        return "/{$}"
+    path = re.sub(r"\{(\w+):[^}]*\}", r"{\1}", path)
+    segments = path.split("/")
+    names, wildcards = [], 0
+    for i, segment in enumerate(segments):
+        if segment == "**" and i == len(segments) - 1:
+            segment = "{rest...}"
+        elif segment == "*":
+            wildcards += 1
+            segment = f"{{wildcard{wildcards}}}"
+        wildcard = WILDCARD_SEGMENT_RE.fullmatch(segment)
+        if wildcard:
+            names.append(wildcard.group(1))
+        elif re.search(r"[{}*]", segment):
+            return None
+        segments[i] = segment
+    if len(names) != len(set(names)):
+        return None
+    return "/".join(segments)
+
+
+def _pattern_segments(pattern):
+    """Split a ServeMux pattern into (method or None, [segment]); segments are literals, "{}" or "{...}"."""
+    method, _, path = pattern.rpartition(" ")
+    segments = []
+    for segment in path.split("/")[1:]:
+        if segment == "{$}":
+            segments.append("{$}")
+        elif segment.endswith("...}"):
+            segments.append("{...}")
+        elif segment.startswith("{"):
+            segments.append("{}")
+        else:
+            segments.append(segment)
+    if segments and segments[-1] == "":
+        segments[-1] = "{...}"  # a trailing slash matches the whole subtree
+    return method or None, segments
+
+
+def patterns_conflict(a, b):
+    """
+    True when ServeMux would panic registering both patterns: they can match
+    the same request and neither is more specific (net/http's conflict rule).
+    """
+    (method_a, segs_a), (method_b, segs_b) = _pattern_segments(a), _pattern_segments(b)
+    if method_a and method_b and method_a != method_b:
+        return False
+    a_wins = bool(method_a and not method_b)  # a is more specific somewhere
+    b_wins = bool(method_b and not method_a)
+    for i in range(max(len(segs_a), len(segs_b))):
+        x = segs_a[i] if i < len(segs_a) else None
+        y = segs_b[i] if i < len(segs_b) else None
+        if x == "{...}" or y == "{...}":
+            if x != y:
+                a_wins, b_wins = a_wins or y == "{...}", b_wins or x == "{...}"
+            break
+        if x is None or y is None:
+            return False
+        if x == y:
+            continue
+        if x == "{}":
+            b_wins = True
+        elif y == "{}":
+            a_wins = True
+        else:
+            return False  # different literals (or {$})
+    return a_wins == b_wins
+
+
+def _join(base, path):
+    return "/" + "/".join(p.strip("/") for p in (base, path) if p.strip("/"))
+
+
+def parse_controller(source_code):
+    """
+    Return {"name", "rest", "dependencies": [(type, name)], "routes": [...]} for a
+    Spring controller class, or None. Each route is
+    {"method": "GET" | None, "path", "pattern", "handler", "pathVariables", "queryParams", "body"};
+    pattern is None when the Spring path has no ServeMux equivalent.
+    """
+    if not is_controller(source_code):
+        return None
+    class_match = CLASS_RE.search(source_code)
+    if not class_match:
+        return None
+
+    header = source_code[: class_match.start()]
+    base_paths = [""]
+    for m in MAPPING_RE.finditer(header):
+        if m.group("kind") == "Request":
+            base_paths = _mapping_paths(m.group("args"))
+
+    routes = []
+    body = source_code[class_match.end():]
+    for m in MAPPING_RE.finditer(body):
+        method_match = METHOD_AFTER_RE.match(body, m.end())
+        if not method_match:
+            continue
+        params = method_match.group("params")
+        if m.group("kind") == "Request":
+            methods = _mapping_methods(m.group("args")) or [None]
+        else:
+            methods = [HTTP_METHODS[m.group("kind")]]
+
+        body_match = REQUEST_BODY_RE.search(params)
+        for base in base_paths:
+            for path in _mapping_paths(m.group("args")):
+                for http_method in methods:
+                    spring_path = _join(base, path)
+                    routes.append({
+                        "method": http_method,
+                        "path": spring_path,
+                        "pattern": to_go_pattern(spring_path),
+                        "handler": exported(method_match.group(1)),
+                        "pathVariables": [_arg_name(p.group("args"), p.group("name"))
+                                          for p in PATH_VARIABLE_RE.finditer(params)],
+                        "queryParams": [_arg_name(p.group("args"), p.group("name"))
+                                        for p in REQUEST_PARAM_RE.finditer(params)],
+                        "body": body_match.group("type") if body_match else None,
+                    })
+
+    dependencies = []
+    for m in list(AUTOWIRED_FIELD_RE.finditer(body)) + list(FINAL_FIELD_RE.finditer(body)):
+        dep = (re.sub(r"<.*", "", m.group("type")), m.group("name"))
+        if dep not in dependencies:
+            dependencies.append(dep)
+
+    return {
+        "name": class_match.group(1),
+        "rest": bool(REST_CONTROLLER_RE.search(source_code)),
+        "dependencies": dependencies,
+        "routes": routes,
+    }
+
+
+def build_controller_rules(source_code):
+    """Return the prompt section describing how to emit this controller, or ''."""
+    controller = parse_controller(source_code)
+    if not controller:
+        return ""
+
+    name = controller["name"]
+    deps = ", ".join(f"{dep_name} {dep_type}" for dep_type, dep_name in controller["dependencies"])
+    lines = [
+        f"This is a Spring MVC controller. Emit it as net/http handlers:",
+        f"- type {name} struct holding its injected services as fields; constructor func New{name}({deps}) *{name}",
+        "- each request-mapped method → func (c *" + name + ") <Method>(w http.ResponseWriter, r *http.Request)",
+        "- @PathVariable → r.PathValue(\"name\"); @RequestParam → r.URL.Query().Get(\"name\") with strconv for numbers",
+        "- @RequestBody → json.NewDecoder(r.Body).Decode(&v), replying 400 on decode errors",
+        "- errors from services → http.Error with a matching status (404 for not found, 500 otherwise)",
+        "- do NOT register routes in this file; a generated routes.go calls the handlers by these exact names:",
+    ]
+    for route in controller["routes"]:
+        verb = f"{route['method']} " if route["method"] else ""
+        if route["pattern"] is None:
+            lines.append(f"  {verb}{route['path']} → {name}.{route['handler']} "
+                         "(not registered: ServeMux cannot match this path, so leave a TODO in the handler)")
+        else:
+            lines.append(f"  {verb}{route['pattern']} → {name}.{route['handler']}")
+    if controller["rest"]:
+        lines.append("- responses (@ResponseBody / @RestController) → w.Header().Set(\"Content-Type\", \"application/json\"); json.NewEncoder(w).Encode(result)")
+    else:
+        lines.append("- methods returning a view name → render the html/template with that name from a templates field, "
+                     "passing the Model attributes as a map; redirect:... → http.Redirect")
+    return "\n".join(lines)
+
+
+def generate_routes_file(go_package, controllers, qualify=None):
+    """
+    Build routes.go for the controllers in one Go package: RegisterRoutes mounts
+    every handler, NewRouter constructs the controllers from their services.
+    qualify(java_type) returns the Go type expression for a dependency.
+    """
+    qualify = qualify or (lambda t: f"*{t}")
+
+    # Controllers share a NewRouter parameter only when both name and type match;
+    # a name reused for a different type (two `service` fields) gets a type-based name.
+    dep_params, param_names, taken = [], {}, {"mux"}
+    for c in controllers:
+        for dep_type, dep_name in c["dependencies"]:
+            key = (dep_name, qualify(dep_type))
+            if key in param_names:
+                continue
+            name = dep_name if dep_name not in taken else _var(dep_type)
+            suffix = 2
+            while name in taken:
+                name = f"{_var(dep_type)}{suffix}"
+                suffix += 1
+            taken.add(name)
+            param_names[key] = name
+            dep_params.append((name, key[1]))
+
+    out = [
+        "// Code generated by Shadow-Code from Spring MVC request mappings. DO NOT EDIT.",
+        "",
+        f"package {go_package}",
+        "",
+        'import "net/http"',
+        "",
+        "// RegisterRoutes mounts every converted controller handler on mux.",
+        "func RegisterRoutes(mux *http.ServeMux, " +
+        ", ".join(f"{_var(c['name'])} *{c['name']}" for c in controllers) + ") {",
+    ]
+    registered = []  # (pattern, "Controller.Handler")
+    for c in controllers:
+        for route in c["routes"]:
+            verb = f"{route['method']} " if route["method"] else ""
+            if route["pattern"] is None:
+                # Registering it would make NewRouter panic.
+                out.append(f"\t// TODO: {verb}{route['path']} → {c['name']}.{route['handler']}: "
+                           "ServeMux cannot express this Spring path; route it by hand")
+                continue
+            pattern = f"{verb}{route['pattern']}"
+            clash = next((r for r in registered if patterns_conflict(r[0], pattern)), None)
+            if clash:
+                # ServeMux panics on conflicting patterns; Spring resolved these by params/headers or order.
+                out.append(f"\t// TODO: {pattern} → {c['name']}.{route['handler']} conflicts with "
+                           f"{clash[0]} → {clash[1]}; route it by hand")
+                continue
+            registered.append((pattern, f"{c['name']}.{route['handler']}"))
+            out.append(f'\tmux.HandleFunc("{pattern}", {_var(c["name"])}.{route["handler"]})')
+    out += ["}", ""]
+
+    out += [
+        "// NewRouter wires the controllers to their services and returns a ready mux.",
+        "func NewRouter(" + ", ".join(f"{n} {t}" for n, t in dep_params) + ") *http.ServeMux {",
+        "\tmux := http.NewServeMux()",
+        "\tRegisterRoutes(mux, " + ", ".join(
+            f"New{c['name']}(" + ", ".join(param_names[(n, qualify(t))] for t, n in c["dependencies"]) + ")"
+            for c in controllers
+        ) + ")",
+        "\treturn mux",
+        "}",
+        "",
+    ]
+    return "\n".join(out)
+
+
+def _var(type_name):
+    return type_name[:1].lower() + type_name[1:]
@@ -0,0 +1,143 @@
+# backend/type_hierarchy.py
+"""
+Detect Java interfaces, abstract classes and their implementations, and turn
+them into explicit Go translation rules for the conversion prompt:
+
+- interface            → Go interface (extended interfaces are embedded)
+- abstract class Foo   → interface Foo + FooBase struct with the shared state
+- class Impl extends/implements → embeds FooBase, plus a compile-time check
+                                  var _ Foo = (*Impl)(nil)
+"""
+import re
+
+DECL_RE = re.compile(
+    r"(?P<mods>(?:(?:public|protected|private|static|final|abstract|sealed|non-sealed|strictfp)\s+)*)"
+    r"(?P<kind>class|interface)\s+(?P<name>[A-Z]\w*)\s*(?:<[^{]*?>)?"
+    r"(?:\s+extends\s+(?P<extends>[^{]+?))?"
+    r"(?:\s+implements\s+(?P<implements>[^{]+?))?"
+    r"(?:\s+permits\s+[^{]+?)?\s*\{",
+)
+ABSTRACT_METHOD_RE = re.compile(r"\babstract\s+[\w<>\[\],.?\s]+?\s+(\w+)\s*\(")
+DEFAULT_METHOD_RE = re.compile(r"\bdefault\s+[\w<>\[\],.?\s]+?\s+(\w+)\s*\(")
+
+
+def _split_types(type_list):
+    """Split 'A<B, C>, D' into ['A', 'D'] (generic arguments and qualifiers dropped)."""
+    if not type_list:
+        return []
+    names, depth, current = [], 0, ""
+    for ch in type_list:
+        if ch == "<":
+            depth += 1
+        elif ch == ">":
+            depth -= 1
+        elif ch == "," and depth == 0:
+            names.append(current)
+            current = ""
+        elif depth == 0:
+            current += ch
+    names.append(current)
+    return [n.strip().split(".")[-1] for n in names if n.strip()]
+
+
+def block_body(source_code, open_brace):
+    """Text between the brace at open_brace and its matching close brace."""
+    depth = 0
+    for i in range(open_brace, len(source_code)):
+        if source_code[i] == "{":
+            depth += 1
+        elif source_code[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return source_code[open_brace + 1:i]
+    return source_code[open_brace + 1:]
+
+
+def parse_type_declarations(source_code):
+    """
+    Return the type declarations in a Java file as a list of
+    {"name", "kind": "interface" | "abstract" | "class", "extends", "implements", "body"}.
+    """
+    decls = []
+    for m in DECL_RE.finditer(source_code):
+        mods = m.group("mods").split()
+        if m.group("kind") == "interface":
+            kind = "interface"
+        elif "abstract" in mods:
+            kind = "abstract"
+        else:
+            kind = "class"
+        decls.append({
+            "name": m.group("name"),
+            "kind": kind,
+            "extends": _split_types(m.group("extends")),
+            "implements": _split_types(m.group("implements")),
+            "body": block_body(source_code, m.end() - 1),
+        })
+    return decls
+
+
+def build_hierarchy_rules(source_code, known_kinds=None):
+    """
+    Return the prompt section describing how this file's type hierarchy maps to
+    Go, or ''. known_kinds optionally maps type names declared in other files to
+    their kind, so a superclass from elsewhere is recognised as abstract.
+    """
+    known_kinds = dict(known_kinds or {})
+    decls = parse_type_declarations(source_code)
+    for d in decls:
+        known_kinds[d["name"]] = d["kind"]
+
+    lines = []
+    for d in decls:
+        name = d["name"]
+        if d["kind"] == "interface":
+            line = f"- interface {name} → Go interface {name} with the same method set"
+            # Only project interfaces can be embedded; Serializable, Comparable etc. have no Go type.
+            embedded = [e for e in d["extends"] if known_kinds.get(e) == "interface"]
+            if embedded:
+                line += f"; embed {', '.join(embedded)}"
+            lines.append(line)
+            defaults = DEFAULT_METHOD_RE.findall(d["body"])
+            if defaults:
+                lines.append(
+                    f"  default methods ({', '.join(defaults)}) → package-level functions taking a {name}, "
+                    f"since Go interfaces carry no implementations"
+                )
+        elif d["kind"] == "abstract":
+            abstract_methods = ABSTRACT_METHOD_RE.findall(d["body"])
+            line = (
+                f"- abstract class {name} → Go interface {name} (all public methods) plus struct {name}Base "
+                f"holding its fields and concrete methods"
+            )
+            project_ifaces = [i for i in d["implements"] if i in known_kinds]
+            if project_ifaces:
+                line += f"; interface {name} embeds {', '.join(project_ifaces)}"
+            lines.append(line)
+            if abstract_methods:
+                lines.append(
+                    f"  abstract methods ({', '.join(abstract_methods)}) stay only on the interface; if a concrete "
+                    f"method calls one (template method), pass the {name} interface into it instead of using the receiver"
+                )
+
+        supers = [s for s in d["extends"] if d["kind"] != "interface"]
+        holder = f"{name}Base" if d["kind"] == "abstract" else name
+        for parent in supers:
+            parent_kind = known_kinds.get(parent)
+            if parent_kind == "abstract":
+                lines.append(f"- {name} extends abstract {parent} → embed {parent}Base in {holder}")
+            elif parent_kind:
+                lines.append(f"- {name} extends {parent} → embed {parent} in {holder}")
+            elif re.search(r"(?:Exception|Error|Throwable)$", parent):
+                lines.append(f"- {name} extends {parent} → do not embed it; {holder} implements error "
+                             f"with an Error() string method")
+
+        if d["kind"] != "class":
+            continue
+        # Only project types get assertions; JDK interfaces (Comparable, Serializable) have no Go counterpart.
+        implemented = [i for i in d["implements"] if known_kinds.get(i) in ("interface", "abstract")]
+        implemented += [p for p in supers if known_kinds.get(p) == "abstract"]
+        for iface in implemented:
+            lines.append(f"- {name} implements {iface} → add var _ {iface} = (*{name})(nil) after the type")
+
+    return "\n".join(lines)
@@ -0,0 +1,159 @@
+# backend/type_mappings.py
+"""
+Java → Go type-mapping rules injected into the Gemini conversion prompt.
+
+Gemini left to itself maps java.math.BigDecimal to *big.Float, which is a
+binary float and silently changes money semantics. The mappings below pin
+BigDecimal to a decimal type and spell out how its API translates.
+"""
+
+import re
+
+from go_imports import is_import_path, package_name_for
+
+DEFAULT_DECIMAL_TYPE = "shopspring"
+
+# Defaults for other JDK types; a conversion config's typeMappings override these.
+DEFAULT_TYPE_MAPPINGS = {
+    "java.util.Date": {"goType": "time.Time", "import": "time"},
+    "java.sql.Timestamp": {"goType": "time.Time", "import": "time"},
+    "java.time.LocalDate": {"goType": "time.Time", "import": "time"},
+    "java.time.LocalDateTime": {"goType": "time.Time", "import": "time"},
+    "java.time.Instant": {"goType": "time.Time", "import": "time"},
+    "java.time.Duration": {"goType": "time.Duration", "import": "time"},
+}
+
+DECIMAL_MAPPINGS = {
+    "shopspring": {
+        "goType": "decimal.Decimal",
+        "import": "github.com/shopspring/decimal",
+        "rules": [
+            "Use decimal.Decimal values (not pointers) for BigDecimal fields, params and returns",
+            'new BigDecimal("1.23") → decimal.RequireFromString("1.23")',
+            "new BigDecimal(int) / BigDecimal.valueOf(long) → decimal.NewFromInt(n)",
+            "BigDecimal.valueOf(double) → decimal.NewFromFloat(f)",
+            "BigDecimal.ZERO → decimal.Zero, BigDecimal.ONE → decimal.NewFromInt(1), BigDecimal.TEN → decimal.NewFromInt(10)",
+            "a.add(b) → a.Add(b), a.subtract(b) → a.Sub(b), a.multiply(b) → a.Mul(b)",
+            "a.divide(b, scale, mode) → a.DivRound(b, int32(scale)); a.divide(b) → a.Div(b)",
+            "a.setScale(n, RoundingMode.HALF_UP) → a.Round(n)",
+            "a.setScale(n, RoundingMode.HALF_EVEN) → a.RoundBank(n)",
+            "a.setScale(n, RoundingMode.DOWN) → a.Truncate(n), UP → a.RoundUp(n)",
+            "a.setScale(n, RoundingMode.FLOOR) → a.RoundFloor(n), CEILING → a.RoundCeil(n)",
+            "a.compareTo(b) → a.Cmp(b); a.compareTo(b) == 0 → a.Equal(b); a.compareTo(b) > 0 → a.GreaterThan(b)",
+            "a.equals(b) → a.Equal(b) (note: Java equals also compares scale; add a comment where that matters)",
+            "a.signum() → a.Sign(), a.negate() → a.Neg(), a.abs() → a.Abs()",
+            "a.toString() / a.toPlainString() → a.String(); a.setScale(n).toString() → a.StringFixed(n)",
+            "a.intValue() → a.IntPart(), a.doubleValue() → a.InexactFloat64()",
+        ],
+    },
+    "bigfloat": {
+        "goType": "*big.Float",
+        "import": "math/big",
+        "rules": [
+            "Use *big.Float for BigDecimal fields, params and returns",
+            "a.add(b) → new(big.Float).Add(a, b), subtract → Sub, multiply → Mul, divide → Quo",
+            "a.compareTo(b) → a.Cmp(b)",
+            "a.setScale(n, mode) → add a TODO: big.Float has no decimal scale; round when formatting with a.Text('f', n)",
+        ],
+    },
+}
+
+
+def parse_type_spec(spec):
+    """
+    Normalise a Go type spec to {"goType", "import"}. Accepts a dict with those
+    keys, an "import/path.Type" string (prefix "*" for a pointer, as in
+    "*math/big.Float"), or a builtin such as "string" or "int64".
+    """
+    if isinstance(spec, dict):
+        if not spec.get("goType"):
+            raise ValueError(f"Type mapping is missing goType: {spec}")
+        if spec.get("import") and not is_import_path(spec["import"]):
+            raise ValueError(f"Invalid import path in type mapping: {spec['import']!r}")
+        return {"goType": spec["goType"], "import": spec.get("import")}
+
+    if not isinstance(spec, str) or not spec.strip():
+        raise ValueError(f"Invalid type mapping: {spec!r}")
+
+    spec = spec.strip()
+    pointer = "*" if spec.startswith("*") else ""
+    import_path, _, type_name = spec[len(pointer):].rpartition(".")
+    if not import_path:
+        if not re.fullmatch(r"(?:\*|\[\])*[A-Za-z_]\w*", spec):
+            raise ValueError(f"Invalid type mapping: {spec!r} (expected a Go type or import/path.Type)")
+        return {"goType": spec, "import": None}
+    if not re.fullmatch(r"[A-Za-z_]\w*", type_name):
+        raise ValueError(f"Invalid type mapping: {spec!r} (expected import/path.Type)")
+    if not is_import_path(import_path):
+        raise ValueError(f"Invalid import path in type mapping: {spec!r}")
+    return {"goType": f"{pointer}{package_name_for(import_path)}.{type_name}", "import": import_path}
+
+
+def resolve_decimal_mapping(decimal_type=None):
+    """
+    Return the mapping for a decimal option. Accepts a known key
+    ("shopspring", "bigfloat"), a custom "import/path.Type" spec, or a
+    {"goType", "import"} dict from a conversion config.
+    """
+    if isinstance(decimal_type, str) and decimal_type.strip().lower() in DECIMAL_MAPPINGS:
+        return DECIMAL_MAPPINGS[decimal_type.strip().lower()]
+    if not decimal_type:
+        return DECIMAL_MAPPINGS[DEFAULT_DECIMAL_TYPE]
+
+    mapping = parse_type_spec(decimal_type)
+    if not mapping["import"]:
+        raise ValueError(
+            f"Unknown decimal type: {decimal_type}. Use {', '.join(DECIMAL_MAPPINGS)} or an import/path.Type spec."
+        )
+    type_name = mapping["goType"].split(".")[-1]
+    return {
+        **mapping,
+        "rules": [
+            f"Use {mapping['goType']} for BigDecimal fields, params and returns",
+            f"Translate BigDecimal arithmetic, setScale and compareTo calls to the equivalent {type_name} methods; "
+            f"add a TODO where no equivalent exists",
+        ],
+    }
+
+
+def _format_mapping(java_type, mapping):
+    target = mapping["goType"]
+    if mapping.get("import"):
+        target += f' (import "{mapping["import"]}")'
+    return f"{java_type} → {target}"
+
+
+def resolve_type_mappings(decimal_type=None, overrides=None):
+    """
+    Return {fully-qualified Java type: {"goType", "import"}} for every mapped
+    type. overrides maps Java types to Go type specs and takes precedence over
+    DEFAULT_TYPE_MAPPINGS; an explicit decimal_type wins over an override for
+    java.math.BigDecimal, whose entry also carries its "rules".
+    """
+    overrides = dict(overrides or {})
+    decimal_override = overrides.pop("java.math.BigDecimal", None)
+    mappings = dict(DEFAULT_TYPE_MAPPINGS)
+    mappings.update({java_type: parse_type_spec(spec) for java_type, spec in overrides.items()})
+    mappings["java.math.BigDecimal"] = resolve_decimal_mapping(decimal_type or decimal_override)
+    return mappings
+
+
+def build_type_mapping_rules(source_code, decimal_type=None, overrides=None):
+    """
+    Return the prompt section describing type mappings relevant to this file, or ''.
+    decimal_type and overrides are resolved as in resolve_type_mappings.
+    """
+    mappings = resolve_type_mappings(decimal_type, overrides)
+    lines = []
+
+    decimal = mappings.pop("java.math.BigDecimal")
+    if "BigDecimal" in source_code:
+        lines.append(_format_mapping("java.math.BigDecimal", decimal))
+        lines += [f"  - {rule}" for rule in decimal["rules"]]
+
+    for java_type, mapping in sorted(mappings.items()):
+        simple_name = java_type.split(".")[-1]
+        if re.search(rf"\b{re.escape(simple_name)}\b", source_code):
+            lines.append(_format_mapping(java_type, mapping))
+
+    return "\n".join(lines)